@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/dnsquery"
+	"github.com/spf13/cobra"
+)
+
+const acmeChallengePrefix = "_acme-challenge."
+
+var (
+	acmeNoWait   bool
+	acmeTimeout  time.Duration
+	acmeInterval time.Duration
+)
+
+var acmeCmd = &cobra.Command{
+	Use:   "acme",
+	Short: "Publish and clean up ACME DNS-01 challenge records",
+	Long: `Hooks for ACME clients such as lego and certbot. The zone is detected from
+the name, so -d is not needed. Names without the _acme-challenge. prefix get
+it added.
+
+Examples:
+  cfcli acme present _acme-challenge.www.example.com. <token>
+  cfcli acme cleanup _acme-challenge.www.example.com. <token>
+
+  # certbot --manual-auth-hook / --manual-cleanup-hook
+  cfcli acme present "$CERTBOT_DOMAIN" "$CERTBOT_VALIDATION"
+  cfcli acme cleanup "$CERTBOT_DOMAIN" "$CERTBOT_VALIDATION"`,
+}
+
+var acmePresentCmd = &cobra.Command{
+	Use:   "present <fqdn> <value>",
+	Short: "Create the challenge TXT record and wait until it is served",
+	Long: `Create the challenge TXT record and wait until every Cloudflare
+authoritative nameserver for the zone returns it.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := acmeRecordName(args[0])
+		value := args[1]
+
+		client, err := newACMEClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+
+		zone, err := client.SetZoneForName(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		existing, err := findACMERecords(ctx, client, name, value)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			fmt.Printf("✓ TXT record %s already has the challenge value\n", name)
+		} else {
+			// Short TTL unless asked otherwise, so retries see new values quickly.
+			recordTTL := 120
+			if cmd.Flags().Changed("ttl") {
+				recordTTL = int(ttl)
+			}
+			record, err := client.AddDNSRecord(ctx, "TXT", name, value, recordTTL, nil, false)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✓ Created TXT record: %s (zone %s, ID %s)\n", record.Name, zone.Name, record.ID)
+		}
+
+		if acmeNoWait {
+			return nil
+		}
+		return waitForTXT(ctx, zone.NameServers, name, value)
+	},
+}
+
+var acmeCleanupCmd = &cobra.Command{
+	Use:   "cleanup <fqdn> <value>",
+	Short: "Remove the challenge TXT record with the given value",
+	Long: `Remove the challenge TXT record whose value matches exactly. Other TXT
+records with the same name, such as those of a concurrent challenge, are left
+alone.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := acmeRecordName(args[0])
+		value := args[1]
+
+		client, err := newACMEClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+
+		if _, err := client.SetZoneForName(ctx, name); err != nil {
+			return err
+		}
+
+		records, err := findACMERecords(ctx, client, name, value)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			fmt.Printf("No TXT record %s with the challenge value; nothing to remove\n", name)
+			return nil
+		}
+		for _, record := range records {
+			if err := client.DeleteDNSRecord(ctx, record.ID); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Deleted TXT record: %s\n", record.Name)
+		}
+		return nil
+	},
+}
+
+func newACMEClient() (*cloudflare.Client, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+	}
+	return newClient()
+}
+
+// acmeRecordName normalizes the name passed by an ACME client into the
+// challenge record name.
+func acmeRecordName(fqdn string) string {
+	name := strings.ToLower(strings.TrimSuffix(fqdn, "."))
+	name = strings.TrimPrefix(name, "*.")
+	if !strings.HasPrefix(name, acmeChallengePrefix) {
+		name = acmeChallengePrefix + name
+	}
+	return name
+}
+
+// findACMERecords returns the TXT records at name whose value is exactly
+// value.
+func findACMERecords(ctx context.Context, client *cloudflare.Client, name, value string) ([]cloudflare.DNSRecord, error) {
+	records, err := client.FindDNSRecord(ctx, name, "", "TXT")
+	if err != nil {
+		return nil, err
+	}
+	var matches []cloudflare.DNSRecord
+	for _, record := range records {
+		if dnsquery.UnquoteTXT(record.Content) == value {
+			matches = append(matches, record)
+		}
+	}
+	return matches, nil
+}
+
+// waitForTXT polls every nameserver until each returns value for name.
+func waitForTXT(ctx context.Context, nameservers []string, name, value string) error {
+	if len(nameservers) == 0 {
+		return fmt.Errorf("zone has no assigned nameservers to check")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, acmeTimeout)
+	defer cancel()
+
+	qtype, err := dnsquery.Type("TXT")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Waiting for %s on %s ...\n", name, strings.Join(nameservers, ", "))
+	pending := append([]string(nil), nameservers...)
+	for {
+		var still []string
+		for _, ns := range pending {
+			answer, err := dnsquery.Query(ctx, ns, name, qtype)
+			if err == nil && slices.Contains(answer.Values, value) {
+				fmt.Printf("  ✓ %s\n", ns)
+				continue
+			}
+			still = append(still, ns)
+		}
+		pending = still
+		if len(pending) == 0 {
+			fmt.Println("✓ Challenge record is visible on all authoritative nameservers")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s waiting for %s on %s", acmeTimeout, name, strings.Join(pending, ", "))
+		case <-time.After(acmeInterval):
+		}
+	}
+}
+
+func init() {
+	acmePresentCmd.Flags().BoolVar(&acmeNoWait, "no-wait", false, "Do not wait for the record to reach the authoritative nameservers")
+	acmePresentCmd.Flags().DurationVar(&acmeTimeout, "timeout", 2*time.Minute, "How long to wait for the record to be served")
+	acmePresentCmd.Flags().DurationVar(&acmeInterval, "poll-interval", 2*time.Second, "Time between nameserver checks")
+
+	acmeCmd.AddCommand(acmePresentCmd)
+	acmeCmd.AddCommand(acmeCleanupCmd)
+	rootCmd.AddCommand(acmeCmd)
+}
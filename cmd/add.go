@@ -3,8 +3,8 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net"
 
-	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
 	"github.com/spf13/cobra"
 )
 
@@ -13,7 +13,11 @@ var addCmd = &cobra.Command{
 	Short: "Add a DNS record",
 	Long: `Add a new DNS record to your domain.
 	
+The zone is detected from fully-qualified names, and the type defaults to
+A or AAAA when the content is an IP address.
+
 Examples:
+  cfcli add api.staging.example.com 1.2.3.4
   cfcli -d example.com -t A add mail 1.2.3.4
   cfcli -d example.com -t CNAME add www example.com
   cfcli -d example.com -t MX -p 10 add @ mail.example.com
@@ -21,25 +25,28 @@ Examples:
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if cfg.Token == "" {
-			return fmt.Errorf("API token is required (use -k or set CF_API_KEY)")
-		}
-		if cfg.Domain == "" {
-			return fmt.Errorf("domain is required (use -d or set CF_API_DOMAIN)")
-		}
-		if recordType == "" {
-			return fmt.Errorf("record type is required (use -t)")
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
 		}
 
 		name := args[0]
 		content := args[1]
 
-		client, err := cloudflare.NewClient(cfg.Token, cfg.Email)
+		addType := recordType
+		if addType == "" {
+			addType = inferRecordType(content)
+		}
+		if addType == "" {
+			return fmt.Errorf("record type is required (use -t)")
+		}
+
+		client, err := newClient()
 		if err != nil {
 			return err
 		}
 		ctx := context.Background()
 
-		if err := client.SetZone(ctx, cfg.Domain); err != nil {
+		name, err = selectZone(ctx, client, name)
+		if err != nil {
 			return err
 		}
 
@@ -49,7 +56,7 @@ Examples:
 			priorityPtr = &p
 		}
 
-		record, err := client.AddDNSRecord(ctx, recordType, name, content, int(ttl), priorityPtr, activate)
+		record, err := client.AddDNSRecord(ctx, addType, name, content, recordTTL(cmd), priorityPtr, recordProxied(cmd))
 		if err != nil {
 			return err
 		}
@@ -61,10 +68,27 @@ Examples:
 		fmt.Println()
 		fmt.Printf("  Record ID: %s\n", record.ID)
 
+		if checkWait {
+			return checkPropagation(ctx, client, name, addType, true)
+		}
 		return nil
 	},
 }
 
+// inferRecordType returns A or AAAA when content is an IP address.
+func inferRecordType(content string) string {
+	ip := net.ParseIP(content)
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return "A"
+	default:
+		return "AAAA"
+	}
+}
+
 func init() {
+	addPropagationFlags(addCmd)
 	rootCmd.AddCommand(addCmd)
 }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/spf13/cobra"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect the credentials cfcli is using",
+}
+
+var authVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the API token and show its permissions",
+	Long: `Verify the API token with Cloudflare and show its status, validity window,
+policies (when the token may read its own details) and the zones it can access.
+
+Examples:
+  cfcli auth verify
+  cfcli -u work auth verify`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Token == "" {
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+
+		if cfg.Email != "" {
+			userEmail, err := client.VerifyAPIKey(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("✓ Global API key is valid for %s\n", userEmail)
+			fmt.Println("  Global API keys have full account access; consider a scoped API token instead.")
+			return nil
+		}
+
+		info, err := client.VerifyToken(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Token ID:    %s\n", info.ID)
+		fmt.Printf("Status:      %s\n", info.Status)
+		if info.NotBefore != nil {
+			fmt.Printf("Not before:  %s\n", formatTokenTime(info.NotBefore))
+		}
+		fmt.Printf("Expires:     %s\n", formatTokenTime(info.ExpiresOn))
+
+		zones, zonesErr := client.ListZones(ctx)
+		zoneNames := cloudflare.ZoneNames(zones)
+
+		fmt.Println()
+		if info.PoliciesErr != nil {
+			fmt.Printf("Policies:    unavailable (%v)\n", info.PoliciesErr)
+		} else {
+			fmt.Println("Policies:")
+			for _, p := range info.Policies {
+				var resources []string
+				for _, r := range p.Resources {
+					resources = append(resources, cloudflare.DescribeResource(r, zoneNames))
+				}
+				fmt.Printf("  %-6s %s\n", p.Effect, strings.Join(p.Permissions, ", "))
+				fmt.Printf("         on %s\n", strings.Join(resources, ", "))
+			}
+		}
+
+		fmt.Println()
+		if zonesErr != nil {
+			fmt.Printf("Zones:       unavailable (%v)\n", zonesErr)
+			return nil
+		}
+		fmt.Printf("Zones (%d):\n", len(zones))
+		domainAccessible := false
+		for _, z := range zones {
+			fmt.Printf("  %s\n", z.Name)
+			if strings.EqualFold(z.Name, cfg.Domain) {
+				domainAccessible = true
+			}
+		}
+		if cfg.Domain != "" {
+			if !domainAccessible {
+				fmt.Printf("\n✗ The token cannot access %s (needs %q on that zone)\n", cfg.Domain, cloudflare.PermZoneRead)
+			}
+		}
+		return nil
+	},
+}
+
+// formatTokenTime formats a token's expiry or start time relative to now.
+// A nil time reads "never", which is only right for the expiry.
+func formatTokenTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	d := time.Until(*t)
+	switch {
+	case d > 0:
+		return fmt.Sprintf("%s (in %s)", t.Format(time.RFC3339), d.Round(time.Minute))
+	default:
+		return fmt.Sprintf("%s (%s ago)", t.Format(time.RFC3339), (-d).Round(time.Minute))
+	}
+}
+
+func init() {
+	authCmd.AddCommand(authVerifyCmd)
+	rootCmd.AddCommand(authCmd)
+}
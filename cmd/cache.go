@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cache"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cacheClearAll bool
+
+	purgeEverything bool
+	purgeURLs       []string
+	purgeFile       string
+	purgeTags       []string
+	purgeHosts      []string
+	purgePrefixes   []string
+	purgeBatchSize  int
+	purgeYes        bool
+)
+
+// purgeRetryDelays are the waits before retrying a purge request the API
+// rate limited, beyond the client's own short retries.
+var purgeRetryDelays = []time.Duration{15 * time.Second, 30 * time.Second, time.Minute}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Purge Cloudflare's cache and manage cfcli's local cache",
+}
+
+var cachePurgeCmd = &cobra.Command{
+	Use:   "purge [url]...",
+	Short: "Purge files from Cloudflare's cache",
+	Long: `Purge cached content for the zone: everything, or by URL, cache tag,
+host name or URL prefix. URLs can be given as arguments, with --url, or one
+per line in a file (--file, "-" for stdin). Large lists are split into
+requests of --batch-size items and sent within the API's rate limits.
+
+Purging by tag, host or prefix requires an Enterprise zone.
+
+Examples:
+  cfcli -d example.com cache purge --everything
+  cfcli -d example.com cache purge https://example.com/app.js https://example.com/app.css
+  git diff --name-only HEAD~1 | sed 's|^public|https://example.com|' | cfcli -d example.com cache purge --file -
+  cfcli -d example.com cache purge --tag release-42 --prefix example.com/assets/`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Token == "" {
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+		}
+		if cfg.Domain == "" {
+			return fmt.Errorf("domain is required (use -d or set CF_API_DOMAIN)")
+		}
+
+		urls := append(append([]string{}, args...), purgeURLs...)
+		if purgeFile != "" {
+			fromFile, err := readPurgeList(cmd, purgeFile)
+			if err != nil {
+				return err
+			}
+			urls = append(urls, fromFile...)
+		}
+		req := cloudflare.PurgeRequest{
+			Everything: purgeEverything,
+			Tags:       purgeTags,
+			Hosts:      purgeHosts,
+			Prefixes:   purgePrefixes,
+		}
+		for _, u := range urls {
+			normalized, err := normalizePurgeURL(u)
+			if err != nil {
+				return err
+			}
+			req.URLs = append(req.URLs, normalized)
+		}
+		switch {
+		case req.Everything && req.Len() > 0:
+			return fmt.Errorf("--everything cannot be combined with URLs, tags, hosts or prefixes")
+		case !req.Everything && req.Len() == 0:
+			return fmt.Errorf("nothing to purge (give URLs, --tag, --host, --prefix or --everything)")
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		if err := client.SetZone(ctx, cfg.Domain); err != nil {
+			return err
+		}
+		zone := client.ZoneName()
+
+		for _, u := range req.URLs {
+			if parsed, err := url.Parse(u); err == nil && !cloudflare.InZone(parsed.Hostname(), zone) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s is not in zone %s\n", u, zone)
+			}
+		}
+
+		if req.Everything {
+			if !purgeYes {
+				ok, err := confirm(cmd, fmt.Sprintf("Purge everything cached for %s?", zone))
+				if err != nil {
+					return err
+				}
+				if !ok {
+					fmt.Println("Purge cancelled")
+					return nil
+				}
+			}
+			if err := client.PurgeCache(ctx, req); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Purged everything for %s\n", zone)
+			return nil
+		}
+
+		batches := req.Batches(purgeBatchSize)
+		for i, batch := range batches {
+			if err := purgeWithRetry(ctx, cmd, client, batch); err != nil {
+				return fmt.Errorf("batch %d of %d: %w (%d of %d items purged)", i+1, len(batches), err, purgedCount(batches[:i]), req.Len())
+			}
+			if len(batches) > 1 {
+				fmt.Printf("  batch %d/%d: %d %s\n", i+1, len(batches), batch.Len(), purgeKind(batch))
+			}
+		}
+		fmt.Printf("✓ Purged %d item(s) from %s in %d request(s)\n", req.Len(), zone, len(batches))
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear the on-disk zone cache",
+	Long: `Clear the on-disk cache of zone IDs and zone lists for the current account,
+or for every account with --all. Entries also expire on their own after ` + cache.DefaultTTL.String() + `.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cacheClearAll {
+			if err := cache.ClearAll(); err != nil {
+				return err
+			}
+			fmt.Println("✓ Cleared the zone cache for all accounts")
+			return nil
+		}
+
+		if cfg.Token == "" {
+			return fmt.Errorf("API token is required to identify the account's cache (use -k, -u or --all)")
+		}
+		c, err := cache.New(cache.Key(cfg.Account, cfg.Token), cache.DefaultTTL)
+		if err != nil {
+			return err
+		}
+		if err := c.Clear(); err != nil {
+			return err
+		}
+		fmt.Println("✓ Cleared the zone cache")
+		return nil
+	},
+}
+
+// purgeWithRetry sends one purge batch, waiting and retrying when the API
+// keeps rate limiting.
+func purgeWithRetry(ctx context.Context, cmd *cobra.Command, client *cloudflare.Client, batch cloudflare.PurgeRequest) error {
+	for attempt := 0; ; attempt++ {
+		err := client.PurgeCache(ctx, batch)
+		if err == nil || !errors.Is(err, cloudflare.ErrRateLimited) || attempt >= len(purgeRetryDelays) {
+			return err
+		}
+		delay := purgeRetryDelays[attempt]
+		fmt.Fprintf(cmd.ErrOrStderr(), "Rate limited; retrying in %s\n", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// readPurgeList reads one URL per line from path, or stdin for "-".
+// Blank lines and lines starting with # are skipped.
+func readPurgeList(cmd *cobra.Command, path string) ([]string, error) {
+	var r io.Reader = cmd.InOrStdin()
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// normalizePurgeURL adds https:// to URLs given without a scheme; the API
+// only matches full URLs.
+func normalizePurgeURL(raw string) (string, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid URL %q", raw)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid URL %q (must be http or https)", raw)
+	}
+	return u.String(), nil
+}
+
+func purgeKind(r cloudflare.PurgeRequest) string {
+	switch {
+	case len(r.Tags) > 0:
+		return "tag(s)"
+	case len(r.Hosts) > 0:
+		return "host(s)"
+	case len(r.Prefixes) > 0:
+		return "prefix(es)"
+	}
+	return "URL(s)"
+}
+
+func purgedCount(batches []cloudflare.PurgeRequest) int {
+	n := 0
+	for _, b := range batches {
+		n += b.Len()
+	}
+	return n
+}
+
+func init() {
+	cacheClearCmd.Flags().BoolVar(&cacheClearAll, "all", false, "Clear the cache of every account")
+
+	cachePurgeCmd.Flags().BoolVar(&purgeEverything, "everything", false, "Purge all cached content for the zone")
+	cachePurgeCmd.Flags().StringSliceVar(&purgeURLs, "url", nil, "URL to purge (repeatable)")
+	cachePurgeCmd.Flags().StringVar(&purgeFile, "file", "", `File with one URL per line ("-" for stdin)`)
+	cachePurgeCmd.Flags().StringSliceVar(&purgeTags, "tag", nil, "Cache tag to purge (repeatable, Enterprise)")
+	cachePurgeCmd.Flags().StringSliceVar(&purgeHosts, "host", nil, "Host name to purge (repeatable, Enterprise)")
+	cachePurgeCmd.Flags().StringSliceVar(&purgePrefixes, "prefix", nil, "URL prefix to purge, without scheme (repeatable, Enterprise)")
+	cachePurgeCmd.Flags().IntVar(&purgeBatchSize, "batch-size", cloudflare.PurgeBatchSize, "Items per API request")
+	cachePurgeCmd.Flags().BoolVarP(&purgeYes, "yes", "y", false, "Do not ask before purging everything")
+
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cachePurgeCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/propagation"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkWait      bool
+	checkTimeout   time.Duration
+	checkInterval  time.Duration
+	checkResolvers []string
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check <name>",
+	Short: "Check whether a record has propagated",
+	Long: `Query the zone's Cloudflare authoritative nameservers and a list of public
+resolvers directly, and compare their answers with the record in the API.
+For proxied records, resolvers are compared with the authoritative answer
+since Cloudflare serves its own edge addresses.
+
+Without -t the type is taken from the name's records, preferring CNAME.
+
+Examples:
+  cfcli check www.example.com
+  cfcli -d example.com -t TXT check _dmarc
+  cfcli check www.example.com --wait --timeout 5m
+  cfcli check www.example.com --resolver 10.0.0.53 --resolver 1.1.1.1`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Token == "" {
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+
+		name, err := selectZone(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+
+		checkType := recordType
+		if checkType == "" {
+			// Check what the name actually has, e.g. a CNAME rather than
+			// the A answers resolvers flatten it to.
+			records, err := client.FindDNSRecord(ctx, name, "", "")
+			if err != nil {
+				return err
+			}
+			checkType = propagation.RecordType(records)
+		}
+		return checkPropagation(ctx, client, name, checkType, checkWait)
+	},
+}
+
+// checkPropagation compares nameserver answers for name with the records
+// in the API and prints the result. With wait it keeps polling until every
+// server agrees or --timeout passes.
+func checkPropagation(ctx context.Context, client *cloudflare.Client, name, checkType string, wait bool) error {
+	checkType = strings.ToUpper(checkType)
+
+	zone, err := client.Zone(ctx)
+	if err != nil {
+		return err
+	}
+	servers := propagation.Servers(zone.NameServers, checkResolvers)
+
+	if wait {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, checkTimeout)
+		defer cancel()
+		fmt.Printf("Waiting for %s %s to propagate to %d servers (timeout %s)...\n", checkType, name, len(servers), checkTimeout)
+	}
+
+	for {
+		records, err := client.FindDNSRecord(ctx, name, "", checkType)
+		if err != nil {
+			return err
+		}
+		expected, predictable := propagation.Expected(records)
+
+		results, err := propagation.Check(ctx, servers, name, checkType, expected)
+		if err != nil {
+			return err
+		}
+		if !predictable {
+			expected = propagation.MatchAuthoritative(results)
+		}
+
+		agreed := 0
+		for _, r := range results {
+			if r.Match {
+				agreed++
+			}
+		}
+
+		if propagation.Agree(results) || !wait {
+			fmt.Printf("Expected: %s\n", formatAnswer(expected, predictable))
+			if err := outputPropagation(results); err != nil {
+				return err
+			}
+			if agreed == len(results) {
+				fmt.Printf("✓ All %d servers agree\n", len(results))
+			} else {
+				fmt.Printf("✗ %d of %d servers agree\n", agreed, len(results))
+			}
+			return nil
+		}
+
+		fmt.Printf("  %d/%d servers agree\n", agreed, len(results))
+		select {
+		case <-ctx.Done():
+			fmt.Printf("Expected: %s\n", formatAnswer(expected, predictable))
+			if err := outputPropagation(results); err != nil {
+				return err
+			}
+			return fmt.Errorf("timed out after %s: %d of %d servers agree", checkTimeout, agreed, len(results))
+		case <-time.After(checkInterval):
+		}
+	}
+}
+
+func formatAnswer(values []string, predictable bool) string {
+	suffix := ""
+	if !predictable {
+		suffix = " (proxied, taken from authoritative answer)"
+	}
+	if len(values) == 0 {
+		return "no records" + suffix
+	}
+	return strings.Join(values, ", ") + suffix
+}
+
+func outputPropagation(results []propagation.Result) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Server", "Role", "Answer", "TTL", "Status")
+	for _, r := range results {
+		answer, ttlStr, status := "-", "-", "✗"
+		switch {
+		case r.Err != nil:
+			answer, status = r.Err.Error(), "error"
+		case len(r.Answer.Values) == 0:
+			answer = r.Answer.Rcode
+		default:
+			answer = strings.Join(r.Answer.Values, ", ")
+			ttlStr = strconv.Itoa(int(r.Answer.TTL))
+		}
+		if r.Match {
+			status = "✓"
+		}
+		if err := table.Append(r.Server.Address, string(r.Server.Role), answer, ttlStr, status); err != nil {
+			return err
+		}
+	}
+	return table.Render()
+}
+
+// addPropagationFlags registers the flags shared by commands that can wait
+// for a record to propagate.
+func addPropagationFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&checkWait, "wait", false, "Wait until all nameservers and resolvers return the record")
+	cmd.Flags().DurationVar(&checkTimeout, "timeout", 5*time.Minute, "How long to wait with --wait")
+	cmd.Flags().DurationVar(&checkInterval, "poll-interval", 5*time.Second, "Time between checks with --wait")
+	cmd.Flags().StringSliceVar(&checkResolvers, "resolver", propagation.DefaultResolvers, "Recursive resolver to check (repeatable)")
+}
+
+func init() {
+	addPropagationFlags(checkCmd)
+	rootCmd.AddCommand(checkCmd)
+}
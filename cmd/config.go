@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/config"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	configForce      bool
+	configSetDefault bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the cfcli configuration file",
+	Long: `Create, inspect and edit the cfcli configuration file and its named accounts.
+
+Examples:
+  cfcli config init -k <token> -d example.com
+  cfcli config add-account work -k <token> -d example.com --default
+  cfcli config use-account work
+  cfcli config set accounts.work.domain example.org
+  cfcli config view
+  cfcli config explain
+  cfcli config validate`,
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a new configuration file",
+	Long: `Create a new configuration file. Values given with -k, -e and -d are
+stored under defaults.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.ResolvePath(cfgFile)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err == nil && !configForce {
+			return fmt.Errorf("config file %s already exists (use --force to overwrite)", path)
+		}
+
+		cf := &config.ConfigFile{}
+		cf.Defaults.Token = token
+		cf.Defaults.Email = email
+		cf.Defaults.Domain = domain
+
+		if err := config.WriteConfigFile(path, cf); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Created config file: %s\n", path)
+		return nil
+	},
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Show the configuration file and effective settings",
+	Long: `Show the configuration file with tokens redacted, followed by the
+effective settings and the source each one came from.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, cf, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		redacted := *cf
+		redacted.Defaults.Token = redactToken(cf.Defaults.Token)
+		redacted.Accounts = make(map[string]config.AccountConfig, len(cf.Accounts))
+		for name, acc := range cf.Accounts {
+			acc.Token = redactToken(acc.Token)
+			redacted.Accounts[name] = acc
+		}
+
+		fmt.Printf("# %s\n", path)
+		out, err := yaml.Marshal(&redacted)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+		fmt.Println()
+
+		fmt.Println("Effective settings:")
+		return outputEffectiveConfig(cfg)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration value",
+	Long: `Set a value in the configuration file. Keys use dotted paths.
+
+Keys:
+  defaults.token, defaults.email, defaults.domain, defaults.account
+  accounts.<name>.token, accounts.<name>.email, accounts.<name>.domain
+  accounts.<name>.domains.<alias>            (zone name for the alias)
+  accounts.<name>.domains.<alias>.ttl        (default TTL for new records)
+  accounts.<name>.domains.<alias>.proxied    (default proxy setting)
+
+Examples:
+  cfcli config set defaults.domain example.com
+  cfcli config set accounts.work.token <token>
+  cfcli config set accounts.work.domains.prod example.com
+  cfcli config set accounts.work.domains.prod.ttl 300`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, cf, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		if err := cf.SetKey(args[0], args[1]); err != nil {
+			return err
+		}
+		if err := config.WriteConfigFile(path, cf); err != nil {
+			return err
+		}
+		value := args[1]
+		if strings.HasSuffix(args[0], ".token") {
+			value = redactToken(value)
+		}
+		fmt.Printf("✓ Set %s = %s\n", args[0], value)
+		return nil
+	},
+}
+
+var configUseAccountCmd = &cobra.Command{
+	Use:   "use-account <name>",
+	Short: "Set the default account",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, cf, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		if err := cf.SetKey("defaults.account", args[0]); err != nil {
+			return err
+		}
+		if err := config.WriteConfigFile(path, cf); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Default account is now %s\n", args[0])
+		return nil
+	},
+}
+
+var configAddAccountCmd = &cobra.Command{
+	Use:   "add-account <name>",
+	Short: "Add a named account",
+	Long: `Add a named account using the values given with -k, -e and -d.
+
+Examples:
+  cfcli config add-account work -k <token> -d example.com
+  cfcli config add-account legacy -k <api-key> -e you@example.com --default`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if token == "" {
+			return fmt.Errorf("API token is required (use -k)")
+		}
+
+		path, cf, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		if _, ok := cf.Accounts[name]; ok && !configForce {
+			return fmt.Errorf("account %q already exists (use --force to overwrite)", name)
+		}
+		if cf.Accounts == nil {
+			cf.Accounts = make(map[string]config.AccountConfig)
+		}
+		cf.Accounts[name] = config.AccountConfig{
+			Token:  token,
+			Email:  email,
+			Domain: domain,
+		}
+		if configSetDefault {
+			cf.Defaults.Account = name
+		}
+
+		if err := config.WriteConfigFile(path, cf); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Added account %s\n", name)
+		return nil
+	},
+}
+
+var configRemoveAccountCmd = &cobra.Command{
+	Use:   "remove-account <name>",
+	Short: "Remove a named account",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		path, cf, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		if _, ok := cf.Accounts[name]; !ok {
+			return fmt.Errorf("account %q does not exist", name)
+		}
+		delete(cf.Accounts, name)
+		if cf.Defaults.Account == name {
+			cf.Defaults.Account = ""
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s was the default account; no default account is set now\n", name)
+		}
+
+		if err := config.WriteConfigFile(path, cf); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Removed account %s\n", name)
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration file for problems",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.ResolvePath(cfgFile)
+		if err != nil {
+			return err
+		}
+		problems, err := config.ValidateConfigFile(path)
+		if err != nil {
+			return err
+		}
+		if len(problems) == 0 {
+			fmt.Printf("✓ %s is valid\n", path)
+			return nil
+		}
+		for _, p := range problems {
+			fmt.Fprintf(cmd.ErrOrStderr(), "✗ %v\n", p)
+		}
+		return fmt.Errorf("%s has %d problem(s)", path, len(problems))
+	},
+}
+
+var configExplainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain where each effective setting comes from",
+	Long: `List every source that provides a value for each setting, in precedence
+order, and mark the one in effect. Sources are consulted in this order:
+
+  1. command-line flags (-k, -e, -d)
+  2. the account selected with -u/--account
+  3. environment variables (CF_API_TOKEN, CLOUDFLARE_API_TOKEN, CF_API_KEY,
+     CF_API_EMAIL, CF_API_DOMAIN)
+  4. the account named in defaults.account
+  5. the top-level defaults
+
+The email is only used when it comes from the same source as the token, or
+is given with -e or CF_API_EMAIL.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Setting", "Value", "Source", "Used")
+		for _, field := range config.Fields {
+			candidates := cfg.Candidates[field]
+			if len(candidates) == 0 {
+				if err := table.Append(field, "-", config.Origin{Source: config.SourceUnset}.String(), ""); err != nil {
+					return err
+				}
+				continue
+			}
+			marked := false
+			for _, c := range candidates {
+				value := c.Value
+				if field == "token" {
+					value = redactToken(value)
+				}
+				used := ""
+				if !marked && c.Origin == cfg.Origin(field) {
+					used = "✓"
+					marked = true
+				}
+				if err := table.Append(field, value, c.Origin.String(), used); err != nil {
+					return err
+				}
+			}
+		}
+		return table.Render()
+	},
+}
+
+func readConfigFile() (string, *config.ConfigFile, error) {
+	path, err := config.ResolvePath(cfgFile)
+	if err != nil {
+		return "", nil, err
+	}
+	cf, err := config.ReadConfigFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, cf, nil
+}
+
+func outputEffectiveConfig(c *config.Config) error {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Setting", "Value", "Source")
+	for _, field := range config.Fields {
+		value := c.Get(field)
+		if field == "token" {
+			value = redactToken(value)
+		}
+		if field == "domain" && c.DomainAlias != "" {
+			value = fmt.Sprintf("%s (alias %s)", value, c.DomainAlias)
+		}
+		if value == "" {
+			value = "-"
+		}
+		if err := table.Append(field, value, c.Origin(field).String()); err != nil {
+			return err
+		}
+	}
+	if c.TTL > 0 {
+		if err := table.Append("ttl", strconv.Itoa(c.TTL), "domain ("+c.Domain+")"); err != nil {
+			return err
+		}
+	}
+	if c.Proxied != nil {
+		if err := table.Append("proxied", strconv.FormatBool(*c.Proxied), "domain ("+c.Domain+")"); err != nil {
+			return err
+		}
+	}
+	return table.Render()
+}
+
+// redactToken hides all but the first and last few characters of a secret.
+func redactToken(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) <= 8 {
+		return "****"
+	}
+	return s[:4] + "****" + s[len(s)-4:]
+}
+
+func init() {
+	configInitCmd.Flags().BoolVar(&configForce, "force", false, "Overwrite an existing config file")
+	configAddAccountCmd.Flags().BoolVar(&configForce, "force", false, "Overwrite an existing account")
+	configAddAccountCmd.Flags().BoolVar(&configSetDefault, "default", false, "Make this the default account")
+
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUseAccountCmd)
+	configCmd.AddCommand(configAddAccountCmd)
+	configCmd.AddCommand(configRemoveAccountCmd)
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configExplainCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/ddns"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ddnsName      string
+	ddnsInterval  time.Duration
+	ddnsOnce      bool
+	ddnsIPv4      bool
+	ddnsIPv6      bool
+	ddnsEndpoints []string
+	ddnsInterface string
+	ddnsLogFormat string
+	ddnsVerbose   bool
+)
+
+var ddnsCmd = &cobra.Command{
+	Use:   "ddns",
+	Short: "Keep a record pointed at this host's public IP",
+	Long: `Detect this host's public IPv4 and/or IPv6 address and keep the matching
+A/AAAA record up to date. Records are only written when the address changes.
+Runs until interrupted (SIGINT/SIGTERM) unless --once is given.
+
+The address is detected by querying IP echo endpoints (--ip-url) or by
+reading a local network interface (--interface).
+
+Examples:
+  cfcli -d example.com ddns --name home --interval 5m
+  cfcli -d example.com ddns --name home --ipv6 --once
+  cfcli -d example.com ddns --name vpn --interface eth0 --log-format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Token == "" {
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+		}
+		if ddnsName == "" {
+			return fmt.Errorf("record name is required (use --name)")
+		}
+		if !ddnsIPv4 && !ddnsIPv6 {
+			return fmt.Errorf("at least one of --ipv4 or --ipv6 must be enabled")
+		}
+		if !ddnsOnce && ddnsInterval < 10*time.Second {
+			return fmt.Errorf("interval must be at least 10s")
+		}
+
+		logger, err := newDDNSLogger()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		name, err := selectZone(ctx, client, ddnsName)
+		if err != nil {
+			return err
+		}
+
+		var families []ddns.Family
+		if ddnsIPv4 {
+			families = append(families, ddns.IPv4)
+		}
+		if ddnsIPv6 {
+			families = append(families, ddns.IPv6)
+		}
+
+		updater := &ddns.Updater{
+			Store: client,
+			Detector: &ddns.Detector{
+				Interface: ddnsInterface,
+				Endpoints: ddnsEndpoints,
+			},
+			Logger:   logger,
+			Name:     name,
+			Families: families,
+			TTL:      recordTTL(cmd),
+			Proxied:  recordProxied(cmd),
+		}
+
+		if ddnsOnce {
+			return updater.Sync(ctx)
+		}
+		return updater.Run(ctx, ddnsInterval)
+	},
+}
+
+func newDDNSLogger() (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: slog.LevelInfo}
+	if ddnsVerbose {
+		opts.Level = slog.LevelDebug
+	}
+	switch strings.ToLower(ddnsLogFormat) {
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts)), nil
+	case "text", "":
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q (use text or json)", ddnsLogFormat)
+}
+
+func init() {
+	ddnsCmd.Flags().StringVar(&ddnsName, "name", "", "Record name to keep updated (relative to the domain or fully qualified)")
+	ddnsCmd.Flags().DurationVar(&ddnsInterval, "interval", 5*time.Minute, "How often to check the public IP")
+	ddnsCmd.Flags().BoolVar(&ddnsOnce, "once", false, "Check and update once, then exit")
+	ddnsCmd.Flags().BoolVar(&ddnsIPv4, "ipv4", true, "Maintain the A record")
+	ddnsCmd.Flags().BoolVar(&ddnsIPv6, "ipv6", false, "Maintain the AAAA record")
+	ddnsCmd.Flags().StringSliceVar(&ddnsEndpoints, "ip-url", nil, "IP echo endpoint to query, in order (repeatable; default "+strings.Join(ddns.DefaultEndpoints, ", ")+")")
+	ddnsCmd.Flags().StringVar(&ddnsInterface, "interface", "", "Read the address from this network interface instead of an echo endpoint")
+	ddnsCmd.Flags().StringVar(&ddnsLogFormat, "log-format", "text", "Log format: text, json")
+	ddnsCmd.Flags().BoolVarP(&ddnsVerbose, "verbose", "v", false, "Also log checks where nothing changed")
+	rootCmd.AddCommand(ddnsCmd)
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/dnsquery"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/dnssec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dnssecResolver string
+	dnssecYes      bool
+)
+
+var dnssecCmd = &cobra.Command{
+	Use:   "dnssec",
+	Short: "Manage DNSSEC signing for a zone",
+	Long: `Enable or disable DNSSEC signing and show the DS record to publish at
+the registrar.
+
+Enabling DNSSEC is a two-step process: 'dnssec enable' makes Cloudflare sign
+the zone and prints the DS record, which you then add at the registrar.
+'dnssec verify' checks that the registrar published it correctly.
+
+Before moving the domain to another DNS provider or disabling DNSSEC,
+remove the DS record at the registrar first and wait for its TTL to pass,
+or validating resolvers will fail to resolve the domain.`,
+}
+
+var dnssecStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show DNSSEC state and the DS record for the registrar",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		d, err := client.DNSSEC(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if strings.ToLower(format) == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(d)
+		}
+
+		fmt.Printf("Zone:    %s\n", client.ZoneName())
+		fmt.Printf("DNSSEC:  %s\n", d.Status)
+		if !d.ModifiedOn.IsZero() {
+			fmt.Printf("Changed: %s\n", formatTime(d.ModifiedOn))
+		}
+		switch d.Status {
+		case "disabled":
+			fmt.Println("\nEnable it with 'cfcli dnssec enable'.")
+			return nil
+		case "pending":
+			fmt.Println("\nAdd the DS record below at your registrar; the status turns active once it is published.")
+		case "pending-disabled":
+			fmt.Println("\nDNSSEC is being disabled. Remove the DS record at your registrar if you have not already.")
+		}
+		return printDS(client.ZoneName(), d.DS, d.Flags, d.PublicKey)
+	},
+}
+
+var dnssecEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Start signing the zone and print the DS record",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		d, err := client.SetDNSSEC(context.Background(), true)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Enabled DNSSEC for %s (status: %s)\n", client.ZoneName(), d.Status)
+		if d.DS == "" {
+			fmt.Println("\nThe DS record is not ready yet; run 'cfcli dnssec status' in a few minutes.")
+			return nil
+		}
+		fmt.Println("\nAdd this DS record at your registrar, then run 'cfcli dnssec verify':")
+		return printDS(client.ZoneName(), d.DS, d.Flags, d.PublicKey)
+	},
+}
+
+var dnssecDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop signing the zone",
+	Long: `Stop signing the zone. If the parent zone still publishes a DS record,
+validating resolvers will fail to resolve the domain once signing stops, so
+you are asked to confirm; remove the DS record at the registrar first.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		zone := client.ZoneName()
+
+		published, err := parentDS(ctx, zone)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not check the parent zone for DS records: %v\n", err)
+		}
+		if len(published) > 0 && !dnssecYes {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: the parent zone still publishes %d DS record(s) for %s.\n", len(published), zone)
+			fmt.Fprintln(cmd.ErrOrStderr(), "Disabling DNSSEC now makes the domain fail to resolve for validating resolvers.")
+			ok, err := confirm(cmd, "Disable DNSSEC anyway?")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Cancelled; remove the DS record at your registrar first")
+				return nil
+			}
+		}
+
+		d, err := client.SetDNSSEC(ctx, false)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Disabled DNSSEC for %s (status: %s)\n", zone, d.Status)
+		return nil
+	},
+}
+
+var dnssecVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the parent zone's DS record against Cloudflare's",
+	Long: `Look up the DS records the parent zone (the registry) publishes for the
+domain and compare them with the one Cloudflare signs the zone with. Exits
+non-zero when they do not match, since validating resolvers then fail to
+resolve the domain.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		zone := client.ZoneName()
+		d, err := client.DNSSEC(ctx)
+		if err != nil {
+			return err
+		}
+		published, err := parentDS(ctx, zone)
+		if err != nil {
+			return err
+		}
+
+		if d.Status == "disabled" || d.DS == "" {
+			if len(published) > 0 {
+				return fmt.Errorf("DNSSEC is %s on Cloudflare but the parent zone publishes DS records for %s: remove them at the registrar or enable DNSSEC", d.Status, zone)
+			}
+			fmt.Printf("✓ DNSSEC is %s and the parent zone publishes no DS records for %s\n", d.Status, zone)
+			return nil
+		}
+
+		expected, err := dnssec.ParseDS(d.DS)
+		if err != nil {
+			return err
+		}
+		var key *dnssec.DNSKEY
+		if d.PublicKey != "" {
+			key = &dnssec.DNSKEY{Flags: d.Flags, Algorithm: expected.Algorithm, PublicKey: d.PublicKey}
+		}
+		status, extra := dnssec.Verify(zone, expected, key, published)
+		fmt.Printf("Cloudflare DS:  %s\n", expected)
+		for _, ds := range published {
+			fmt.Printf("Published DS:   %s\n", ds)
+		}
+
+		switch status {
+		case dnssec.Match:
+			fmt.Printf("✓ The parent zone publishes Cloudflare's DS record for %s\n", zone)
+		case dnssec.MatchWithExtra:
+			fmt.Printf("✓ The parent zone publishes Cloudflare's DS record for %s\n", zone)
+			for _, ds := range extra {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: stale DS record %s; remove it at the registrar\n", ds)
+			}
+		case dnssec.Missing:
+			return fmt.Errorf("the parent zone publishes no DS record for %s; add Cloudflare's at the registrar (see 'cfcli dnssec status')", zone)
+		case dnssec.Mismatch:
+			return fmt.Errorf("the parent zone's DS records for %s do not match Cloudflare's; validating resolvers cannot resolve the domain until the registrar publishes %s", zone, expected)
+		}
+		return nil
+	},
+}
+
+// parentDS returns the DS records published for zone, as seen through the
+// configured resolver.
+func parentDS(ctx context.Context, zone string) ([]dnssec.DS, error) {
+	answer, err := dnsquery.Resolver{Server: dnssecResolver}.Query(ctx, zone, "DS")
+	if err != nil {
+		return nil, err
+	}
+	if answer.Rcode != "NOERROR" {
+		return nil, fmt.Errorf("DS lookup for %s returned %s", zone, answer.Rcode)
+	}
+	var records []dnssec.DS
+	for _, v := range answer.Values {
+		ds, err := dnssec.ParseDS(v)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, ds)
+	}
+	return records, nil
+}
+
+// printDS prints the DS record in the forms registrars ask for: a zone
+// file line, its separate fields, and the DNSKEY for registrars that
+// compute the digest themselves.
+func printDS(zone, record string, flags int, publicKey string) error {
+	if record == "" {
+		fmt.Println("\nNo DS record yet.")
+		return nil
+	}
+	ds, err := dnssec.ParseDS(record)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\nDS record:")
+	fmt.Printf("  %s. IN DS %s\n", zone, ds)
+	fmt.Println("\nRegistrar fields:")
+	fmt.Printf("  Key tag:     %d\n", ds.KeyTag)
+	fmt.Printf("  Algorithm:   %d (%s)\n", ds.Algorithm, dnssec.AlgorithmName(ds.Algorithm))
+	fmt.Printf("  Digest type: %d (%s)\n", ds.DigestType, dnssec.DigestTypeName(ds.DigestType))
+	fmt.Printf("  Digest:      %s\n", ds.Digest)
+	if publicKey != "" {
+		fmt.Println("\nDNSKEY (if the registrar asks for the public key):")
+		fmt.Printf("  Flags:       %d\n", flags)
+		fmt.Println("  Protocol:    3")
+		fmt.Printf("  Algorithm:   %d\n", ds.Algorithm)
+		fmt.Printf("  Public key:  %s\n", publicKey)
+	}
+	return nil
+}
+
+func init() {
+	dnssecCmd.PersistentFlags().StringVar(&dnssecResolver, "resolver", "1.1.1.1", "Resolver used to look up the parent zone's DS records")
+	dnssecDisableCmd.Flags().BoolVarP(&dnssecYes, "yes", "y", false, "Do not ask before disabling while a DS record is published")
+
+	dnssecCmd.AddCommand(dnssecStatusCmd, dnssecEnableCmd, dnssecDisableCmd, dnssecVerifyCmd)
+	rootCmd.AddCommand(dnssecCmd)
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	dnsSettingsFile   string
+	dnsSettingsDryRun bool
+)
+
+var dnsSettingsCmd = &cobra.Command{
+	Use:   "dns-settings",
+	Short: "View and change zone-level DNS settings",
+	Long: `View and change the zone's DNS settings: CNAME flattening, the TTL of the
+zone's NS records, custom SOA fields, multi-provider DNS, multi-signer DNSSEC
+and secondary overrides.
+
+Use 'dns-settings export' to capture the settings in a YAML file and
+'dns-settings apply' to bring a zone in line with one.`,
+}
+
+var dnsSettingsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the zone's DNS settings",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		s, err := client.DNSSettings(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if strings.ToLower(format) == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(s)
+		}
+
+		flattening := "root only"
+		if s.FlattenAllCNAMEs != nil && *s.FlattenAllCNAMEs {
+			flattening = "all CNAMEs"
+		}
+		nsTTL := "-"
+		if s.NSTTL != nil {
+			nsTTL = strconv.Itoa(*s.NSTTL)
+		}
+		fmt.Printf("Zone:                 %s\n", client.ZoneName())
+		fmt.Printf("CNAME flattening:     %s\n", flattening)
+		fmt.Printf("NS TTL:               %s\n", nsTTL)
+		fmt.Printf("Multi-provider DNS:   %s\n", cloudflare.FormatOnOff(s.MultiProvider))
+		fmt.Printf("Multi-signer DNSSEC:  %s\n", cloudflare.FormatOnOff(s.MultiSigner))
+		fmt.Printf("Secondary overrides:  %s\n", cloudflare.FormatOnOff(s.SecondaryOverrides))
+		if soa := s.SOA; soa != nil {
+			fmt.Println("SOA:")
+			fmt.Printf("  Primary NS (mname): %s\n", orDash(soa.MName))
+			fmt.Printf("  Contact (rname):    %s\n", orDash(soa.RName))
+			fmt.Printf("  Refresh:            %d\n", soa.Refresh)
+			fmt.Printf("  Retry:              %d\n", soa.Retry)
+			fmt.Printf("  Expire:             %d\n", soa.Expire)
+			fmt.Printf("  Minimum TTL:        %d\n", soa.MinTTL)
+			fmt.Printf("  TTL:                %d\n", soa.TTL)
+		}
+		return nil
+	},
+}
+
+var dnsSettingsSetCmd = &cobra.Command{
+	Use:   "set <setting> <value>",
+	Short: "Change one DNS setting",
+	Long: `Change one DNS setting. Settings:
+
+  cname-flattening     all or root
+  ns-ttl               30-86400
+  multi-provider       on or off
+  multi-signer         on or off (multi-signer DNSSEC)
+  secondary-overrides  on or off
+  soa-mname            primary nameserver host name
+  soa-rname            contact, as a domain name (hostmaster.example.com)
+  soa-refresh          600-86400
+  soa-retry            600-86400
+  soa-expire           86400-2419200
+  soa-min-ttl          60-86400
+  soa-ttl              300-86400
+
+Examples:
+  cfcli -d example.com dns-settings set cname-flattening all
+  cfcli -d example.com dns-settings set soa-rname hostmaster.example.com`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		desired, err := cloudflare.ParseDNSSetting(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		return applyDNSSettings(desired, "")
+	},
+}
+
+var dnsSettingsApplyCmd = &cobra.Command{
+	Use:   "apply -f <file>",
+	Short: "Bring the zone's DNS settings in line with a YAML file",
+	Long: `Apply DNS settings from a YAML file ("-" for stdin), as written by
+'dns-settings export'. Settings left out of the file are not changed, and
+neither are SOA fields left out of its soa section.
+
+Example:
+
+  flatten_all_cnames: true
+  ns_ttl: 3600
+  multi_signer_dnssec: false
+  soa:
+    rname: hostmaster.example.com
+    min_ttl: 300
+
+Examples:
+  cfcli -d example.com dns-settings apply -f dns-settings.yaml --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, data, err := readInputFile(cmd, args, dnsSettingsFile)
+		if err != nil {
+			return err
+		}
+		var desired cloudflare.DNSSettings
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&desired); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if err := desired.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return applyDNSSettings(desired, path)
+	},
+}
+
+var dnsSettingsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the zone's DNS settings as a YAML file for apply",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		s, err := client.DNSSettings(context.Background())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("# DNS settings of %s, for 'cfcli dns-settings apply'.\n", client.ZoneName())
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+		return enc.Close()
+	},
+}
+
+// applyDNSSettings changes the configured zone's DNS settings to the
+// fields set in desired, which came from source ("" for the command line).
+func applyDNSSettings(desired cloudflare.DNSSettings, source string) error {
+	client, err := domainClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	current, err := client.DNSSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	update, changes := cloudflare.PlanDNSSettings(*current, desired)
+	if len(changes) == 0 {
+		if source != "" {
+			fmt.Printf("✓ %s already matches %s\n", client.ZoneName(), source)
+		} else {
+			fmt.Println("No changes")
+		}
+		return nil
+	}
+
+	verb := "✓ Set"
+	if dnsSettingsDryRun {
+		verb = "Would set"
+	} else if err := client.UpdateDNSSettings(ctx, update); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		fmt.Printf("%s %s\n", verb, c)
+	}
+	return nil
+}
+
+func init() {
+	dnsSettingsApplyCmd.Flags().StringVar(&dnsSettingsFile, "file", "", `Settings file ("-" for stdin)`)
+	dnsSettingsSetCmd.Flags().BoolVar(&dnsSettingsDryRun, "dry-run", false, "Show the change without making it")
+	dnsSettingsApplyCmd.Flags().BoolVar(&dnsSettingsDryRun, "dry-run", false, "Show the changes without making them")
+
+	dnsSettingsCmd.AddCommand(dnsSettingsShowCmd, dnsSettingsSetCmd, dnsSettingsApplyCmd, dnsSettingsExportCmd)
+	rootCmd.AddCommand(dnsSettingsCmd)
+}
@@ -1,29 +1,52 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/recordedit"
 	"github.com/spf13/cobra"
 )
 
+var (
+	editInteractive bool
+	editYes         bool
+)
+
 var editCmd = &cobra.Command{
 	Use:   "edit <name> <content>",
 	Short: "Edit a DNS record",
 	Long: `Edit an existing DNS record.
 	
+With --interactive, the zone's records (narrowed by -t and -q) are opened
+as YAML in $VISUAL or $EDITOR. Saved changes are shown as a diff and applied
+after confirmation; records added without an id are created and removed
+entries are deleted. If the file has errors the editor is reopened with them
+marked.
+
 Examples:
   cfcli -d example.com -t A edit mail 5.6.7.8
   cfcli -d example.com -t A -n CNAME edit test example.com  # Change type
-  cfcli -d example.com -t A --ttl 300 edit mail 1.2.3.4     # Set TTL`,
-	Args: cobra.ExactArgs(2),
+  cfcli -d example.com -t A --ttl 300 edit mail 1.2.3.4     # Set TTL
+  cfcli -d example.com edit -i                              # Edit all records
+  cfcli -d example.com -t TXT -q name:_dmarc edit -i`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if editInteractive {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if cfg.Token == "" {
-			return fmt.Errorf("API token is required (use -k or set CF_API_KEY)")
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
 		}
-		if cfg.Domain == "" {
-			return fmt.Errorf("domain is required (use -d or set CF_API_DOMAIN)")
+		if editInteractive {
+			return editInEditor(cmd)
 		}
 		if recordType == "" {
 			return fmt.Errorf("record type is required (use -t)")
@@ -38,13 +61,14 @@ Examples:
 			updateType = newType
 		}
 
-		client, err := cloudflare.NewClient(cfg.Token, cfg.Email)
+		client, err := newClient()
 		if err != nil {
 			return err
 		}
 		ctx := context.Background()
 
-		if err := client.SetZone(ctx, cfg.Domain); err != nil {
+		name, err = selectZone(ctx, client, name)
+		if err != nil {
 			return err
 		}
 
@@ -81,7 +105,7 @@ Examples:
 			proxied = true
 		}
 
-		updated, err := client.UpdateDNSRecord(ctx, record.ID, updateType, name, content, int(ttl), priorityPtr, proxied)
+		updated, err := client.UpdateDNSRecord(ctx, record.ID, updateType, name, content, recordTTL(cmd), priorityPtr, proxied)
 		if err != nil {
 			return err
 		}
@@ -92,10 +116,176 @@ Examples:
 		}
 		fmt.Println()
 
+		if checkWait {
+			return checkPropagation(ctx, client, name, updateType, true)
+		}
 		return nil
 	},
 }
 
+// editInEditor implements edit --interactive.
+func editInEditor(cmd *cobra.Command) error {
+	if cfg.Domain == "" {
+		return fmt.Errorf("domain is required (use -d or set CF_API_DOMAIN)")
+	}
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	if err := client.SetZone(ctx, cfg.Domain); err != nil {
+		return err
+	}
+	zone := client.ZoneName()
+
+	records, err := client.ListDNSRecords(ctx)
+	if err != nil {
+		return err
+	}
+	if query != "" {
+		records = filterRecords(records)
+	}
+	if recordType != "" {
+		var ofType []cloudflare.DNSRecord
+		for _, r := range records {
+			if strings.EqualFold(r.Type, recordType) {
+				ofType = append(ofType, r)
+			}
+		}
+		records = ofType
+	}
+
+	original, err := recordedit.Marshal(zone, records)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp("", "cfcli-edit-*.yaml")
+	if err != nil {
+		return err
+	}
+	path := file.Name()
+	file.Close()
+	keep := false
+	defer func() {
+		if !keep {
+			os.Remove(path)
+		}
+	}()
+
+	data := original
+	var plan *recordedit.Plan
+	for {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return err
+		}
+		if err := runEditor(path); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(edited, original) || recordedit.IsEmpty(edited) {
+			fmt.Println("Edit cancelled, no changes made")
+			return nil
+		}
+
+		entries, err := recordedit.Parse(edited, zone)
+		if err != nil {
+			data = recordedit.Annotate(edited, "the file could not be parsed: "+err.Error(), nil)
+			continue
+		}
+		if problems := recordedit.Validate(entries, records); len(problems) > 0 {
+			data = recordedit.Annotate(edited, fmt.Sprintf("%d problem(s) must be fixed before saving", len(problems)), problems)
+			continue
+		}
+		plan = recordedit.Diff(records, entries)
+		break
+	}
+
+	if plan.Empty() {
+		fmt.Println("No changes")
+		return nil
+	}
+	printEditPlan(plan)
+	if !editYes {
+		ok, err := confirm(cmd, fmt.Sprintf("Apply %d change(s)?", len(plan.Create)+len(plan.Update)+len(plan.Delete)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			keep = true
+			fmt.Printf("Not applied; your edits are saved in %s\n", path)
+			return nil
+		}
+	}
+
+	// Stop at the first failure: later changes may depend on it, e.g. a
+	// CNAME created after the record it replaces was deleted.
+	actions := plan.Actions()
+	for i, a := range actions {
+		var err error
+		done, name, recordType := "", a.Entry.Name, a.Entry.Type
+		switch a.Kind {
+		case "delete":
+			err = client.DeleteDNSRecord(ctx, a.Record.ID)
+			done, name, recordType = "Deleted", a.Record.Name, a.Record.Type
+		case "update":
+			e := a.Entry
+			_, err = client.UpdateDNSRecord(ctx, a.Record.ID, e.Type, e.Name, e.Content, e.TTL, e.Priority, e.Proxied != nil && *e.Proxied)
+			done = "Updated"
+		case "create":
+			e := a.Entry
+			_, err = client.AddDNSRecord(ctx, e.Type, e.Name, e.Content, e.TTL, e.Priority, e.Proxied != nil && *e.Proxied)
+			done = "Created"
+		}
+		if err != nil {
+			keep = true
+			fmt.Fprintf(cmd.ErrOrStderr(), "✗ %s %s: %v\n", a.Kind, name, err)
+			return fmt.Errorf("stopped after %d of %d change(s); your edits are saved in %s", i, len(actions), path)
+		}
+		fmt.Printf("✓ %s %s record: %s\n", done, recordType, name)
+	}
+	return nil
+}
+
+// runEditor opens path in $VISUAL or $EDITOR, falling back to vi.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	c := exec.Command(args[0], append(args[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+func printEditPlan(plan *recordedit.Plan) {
+	for _, a := range plan.Actions() {
+		switch a.Kind {
+		case "delete":
+			fmt.Printf("- %s %s → %s\n", a.Record.Type, a.Record.Name, a.Record.Content)
+		case "update":
+			fmt.Printf("~ %s %s\n", a.Entry.Type, a.Entry.Name)
+			for _, d := range (recordedit.Change{Old: a.Record, New: a.Entry}).Describe() {
+				fmt.Printf("    %s\n", d)
+			}
+		case "create":
+			fmt.Printf("+ %s %s → %s\n", a.Entry.Type, a.Entry.Name, a.Entry.Content)
+		}
+	}
+}
+
 func init() {
+	editCmd.Flags().BoolVarP(&editInteractive, "interactive", "i", false, "Edit the records as YAML in $EDITOR")
+	editCmd.Flags().BoolVarP(&editYes, "yes", "y", false, "Apply interactive edits without asking")
+	addPropagationFlags(editCmd)
 	rootCmd.AddCommand(editCmd)
 }
@@ -0,0 +1,855 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/dnsquery"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/emailauth"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/spf"
+	"github.com/spf13/cobra"
+)
+
+var (
+	emailName     string
+	emailDryRun   bool
+	emailResolver string
+	emailUnset    []string
+	dkimKeyFile   string
+	dkimTesting   bool
+	mtastsID      string
+	mtastsRUA     string
+)
+
+var emailCmd = &cobra.Command{
+	Use:   "email",
+	Short: "Manage SPF, DKIM, DMARC and MTA-STS records",
+	Long: `Read and edit the TXT records that authenticate mail sent from a domain.
+Records are parsed and validated before anything is written, so a typo cannot
+publish a broken policy.
+
+Records are managed for the configured domain, or for the name given with
+--name (e.g. a subdomain that sends mail).
+
+Examples:
+  cfcli -d example.com email spf show
+  cfcli -d example.com email spf add include:_spf.google.com
+  cfcli -d example.com email dmarc set p=reject rua=mailto:dmarc@example.com
+  cfcli -d example.com email dkim add google --key dkim.pub
+  cfcli -d example.com email mta-sts set --rua mailto:tls@example.com`,
+}
+
+var emailSPFCmd = &cobra.Command{
+	Use:   "spf",
+	Short: "Show and edit the SPF policy",
+}
+
+var emailSPFShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the SPF policy term by term",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		name, err := emailDomain()
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		if _, err := selectZone(ctx, client, name); err != nil {
+			return err
+		}
+		record, err := findEmailTXT(ctx, client, name, spf.IsSPF)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return fmt.Errorf("no SPF record at %s", name)
+		}
+		content := dnsquery.UnquoteTXT(record.Content)
+		policy, err := spf.Parse(content)
+		if err != nil {
+			return fmt.Errorf("SPF record at %s is invalid: %w", name, err)
+		}
+		lookups, lookupErr := spf.CountLookups(ctx, policy, dnsquery.Resolver{Server: emailResolver}.LookupTXT)
+
+		if strings.ToLower(format) == "json" {
+			type term struct {
+				Qualifier string `json:"qualifier,omitempty"`
+				Name      string `json:"name"`
+				Value     string `json:"value,omitempty"`
+				Modifier  bool   `json:"modifier"`
+			}
+			out := struct {
+				Name    string `json:"name"`
+				Record  string `json:"record"`
+				Terms   []term `json:"terms"`
+				Lookups int    `json:"lookups"`
+			}{Name: name, Record: content, Lookups: lookups}
+			for _, t := range policy.Terms {
+				q := ""
+				if t.Qualifier != 0 {
+					q = string(t.Qualifier)
+				}
+				out.Terms = append(out.Terms, term{q, t.Name, t.Value, t.Modifier})
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(out)
+		}
+
+		fmt.Printf("%s\n  %s\n\n", name, content)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Term", "Result", "Value", "DNS Lookup")
+		for _, t := range policy.Terms {
+			result := spfResult(t)
+			lookup := ""
+			if t.CausesLookup() {
+				lookup = "yes"
+			}
+			if err := table.Append(t.Name, result, t.Value, lookup); err != nil {
+				return err
+			}
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+		if lookupErr != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not count DNS lookups: %v\n", lookupErr)
+		} else {
+			fmt.Printf("\nDNS lookups: %d of %d allowed\n", lookups, spf.MaxLookups)
+		}
+		return nil
+	},
+}
+
+var emailSPFAddCmd = &cobra.Command{
+	Use:   "add <term>...",
+	Short: "Add mechanisms to the SPF policy",
+	Long: `Add mechanisms to the SPF policy. They are inserted before the "all"
+mechanism. Adding "-all", "~all" or "?all" replaces the current one. A new
+policy ending in ~all is created when none exists.
+
+Examples:
+  cfcli -d example.com email spf add include:_spf.google.com
+  cfcli -d example.com email spf add ip4:192.0.2.0/24 ip6:2001:db8::/32
+  cfcli -d example.com email spf add -- -all`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editSPF(cmd, args, func(policy *spf.Record, t spf.Term) {
+			if !policy.Add(t) {
+				fmt.Printf("%s is already in the policy\n", t)
+			}
+		})
+	},
+}
+
+var emailSPFRemoveCmd = &cobra.Command{
+	Use:     "remove <term>...",
+	Aliases: []string{"rm"},
+	Short:   "Remove mechanisms from the SPF policy",
+	Long: `Remove mechanisms from the SPF policy. A term given without a qualifier
+matches any qualifier.
+
+Examples:
+  cfcli -d example.com email spf remove include:mailgun.org
+  cfcli -d example.com email spf remove ip4:192.0.2.1`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editSPF(cmd, args, func(policy *spf.Record, t spf.Term) {
+			if policy.Remove(t) == 0 {
+				fmt.Printf("%s is not in the policy\n", t)
+			}
+		})
+	},
+}
+
+var emailSPFFlattenCmd = &cobra.Command{
+	Use:   "flatten",
+	Short: "Resolve the SPF policy into address ranges",
+	Long: `Resolve include, a, mx and redirect in the SPF policy into ip4/ip6 ranges
+so that it stays under the 10 DNS lookup limit. The ranges are written to a
+chain of records _spf1.<domain>, _spf2.<domain>, ... each small enough for a
+512-byte DNS response, and the policy at the domain includes _spf1.
+
+The original policy is kept at _spf-source.<domain> and is what later runs
+flatten, so the command can be re-run from cron to pick up changes in the
+providers' ranges. To change the policy, edit the source record and run
+flatten again. Each run reports the ranges added and removed.
+
+Terms that cannot be flattened (ptr, exists, macros and includes of policies
+using them) are kept in the policy as they are, in their original order
+around the ranges. A policy where a fail or softfail term sits between terms
+that pass the same addresses cannot be flattened.
+
+Examples:
+  cfcli -d example.com email spf flatten --dry-run
+  cfcli -d example.com email spf flatten`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		domain, err := emailDomain()
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		if _, err := selectZone(ctx, client, domain); err != nil {
+			return err
+		}
+		records, err := client.ListDNSRecords(ctx)
+		if err != nil {
+			return err
+		}
+
+		root, err := singleTXT(records, domain, spf.IsSPF)
+		if err != nil {
+			return err
+		}
+		sourceName := spfSourcePrefix + domain
+		source, err := singleTXT(records, sourceName, func(string) bool { return true })
+		if err != nil {
+			return err
+		}
+
+		var policyText string
+		switch {
+		case source != nil:
+			policyText = strings.TrimPrefix(dnsquery.UnquoteTXT(source.Content), spfSourceTag)
+		case root == nil:
+			return fmt.Errorf("no SPF record at %s", domain)
+		case strings.Contains(dnsquery.UnquoteTXT(root.Content), "include:"+spf.PartName(domain, 1)):
+			return fmt.Errorf("SPF at %s is already flattened but %s is missing; restore the original policy there", domain, sourceName)
+		default:
+			policyText = dnsquery.UnquoteTXT(root.Content)
+		}
+		policy, err := spf.Parse(policyText)
+		if err != nil {
+			return fmt.Errorf("source SPF policy is invalid: %w", err)
+		}
+
+		flat, err := spf.Flatten(ctx, policy, domain, dnsquery.Resolver{Server: emailResolver})
+		if err != nil {
+			return err
+		}
+		rootContent, parts, err := flat.Split(domain)
+		if err != nil {
+			return err
+		}
+
+		// Existing chain records, by name.
+		oldParts := map[string]*cloudflare.DNSRecord{}
+		oldNets := map[string]bool{}
+		for i := range records {
+			r := &records[i]
+			if !strings.EqualFold(r.Type, "TXT") || !isPartName(r.Name, domain) {
+				continue
+			}
+			oldParts[r.Name] = r
+			if p, err := spf.Parse(dnsquery.UnquoteTXT(r.Content)); err == nil {
+				for _, t := range p.Terms {
+					if t.Name == "ip4" || t.Name == "ip6" {
+						oldNets[t.String()] = true
+					}
+				}
+			}
+		}
+		newNets := map[string]bool{}
+		for _, n := range flat.Networks {
+			newNets[spf.NetworkTerm(n).String()] = true
+		}
+		reportNetworkChanges(oldNets, newNets)
+		for _, t := range flat.Kept {
+			fmt.Printf("  kept as is: %s\n", t)
+		}
+
+		if source == nil {
+			if err := writeEmailTXT(ctx, cmd, client, sourceName, nil, spfSourceTag+policyText); err != nil {
+				return err
+			}
+		}
+		// Write the chain from its end so that no record ever includes a
+		// name that does not exist yet.
+		for i := len(parts) - 1; i >= 0; i-- {
+			p := parts[i]
+			if err := writeEmailTXT(ctx, cmd, client, p.Name, oldParts[p.Name], p.Content); err != nil {
+				return err
+			}
+			delete(oldParts, p.Name)
+		}
+		if err := writeEmailTXT(ctx, cmd, client, domain, root, rootContent); err != nil {
+			return err
+		}
+		for name, r := range oldParts {
+			if emailDryRun {
+				fmt.Printf("Would delete TXT record %s\n", name)
+				continue
+			}
+			if err := client.DeleteDNSRecord(ctx, r.ID); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Deleted TXT record: %s\n", name)
+		}
+
+		lookups := len(parts)
+		for _, t := range flat.Kept {
+			if t.CausesLookup() {
+				lookups++
+			}
+		}
+		fmt.Printf("%d range(s) in %d record(s); %d of %d DNS lookups\n", len(flat.Networks), len(parts), lookups, spf.MaxLookups)
+		return nil
+	},
+}
+
+var emailDMARCCmd = &cobra.Command{
+	Use:   "dmarc",
+	Short: "Show and edit the DMARC policy",
+}
+
+var emailDMARCShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the DMARC policy tag by tag",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showTagRecord(emailauth.DMARCName,
+			emailauth.IsDMARC, emailauth.ParseDMARC, "DMARC")
+	},
+}
+
+var emailDMARCSetCmd = &cobra.Command{
+	Use:   "set <tag=value>...",
+	Short: "Set DMARC policy tags",
+	Long: `Set tags of the DMARC policy, creating the record when none exists (p= is
+then required). Use --unset to remove optional tags.
+
+Tags: p, sp, np (none|quarantine|reject), pct (0-100), rua, ruf (mailto: URIs),
+adkim, aspf (r|s), fo, ri, rf.
+
+Examples:
+  cfcli -d example.com email dmarc set p=quarantine pct=25
+  cfcli -d example.com email dmarc set p=reject rua=mailto:dmarc@example.com
+  cfcli -d example.com email dmarc set --unset ruf`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && len(emailUnset) == 0 {
+			return fmt.Errorf("nothing to change (give tag=value arguments or --unset)")
+		}
+		changes, err := emailauth.ParseAssignments(args)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		domain, err := emailDomain()
+		if err != nil {
+			return err
+		}
+		name := emailauth.DMARCName(domain)
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		if _, err := selectZone(ctx, client, name); err != nil {
+			return err
+		}
+		record, err := findEmailTXT(ctx, client, name, emailauth.IsDMARC)
+		if err != nil {
+			return err
+		}
+
+		var tags emailauth.Tags
+		if record != nil {
+			if tags, err = emailauth.ParseTags(dnsquery.UnquoteTXT(record.Content)); err != nil {
+				return fmt.Errorf("existing DMARC record at %s is malformed: %w", name, err)
+			}
+		} else {
+			p, ok := changes.Get("p")
+			if !ok {
+				return fmt.Errorf("no DMARC record at %s; p= is required to create one", name)
+			}
+			tags = emailauth.NewDMARC(p)
+		}
+		for _, tag := range changes {
+			if tag.Name == "v" {
+				return fmt.Errorf("v cannot be changed")
+			}
+			tags.Set(tag.Name, tag.Value)
+		}
+		for _, tag := range emailUnset {
+			if tag == "v" || tag == "p" {
+				return fmt.Errorf("%s is required and cannot be unset", tag)
+			}
+			tags.Delete(strings.ToLower(tag))
+		}
+		if err := emailauth.ValidateDMARC(tags); err != nil {
+			return fmt.Errorf("refusing to write invalid DMARC record: %w", err)
+		}
+		return writeEmailTXT(ctx, cmd, client, name, record, tags.String())
+	},
+}
+
+var emailDKIMCmd = &cobra.Command{
+	Use:   "dkim",
+	Short: "Show and publish DKIM keys",
+}
+
+var emailDKIMShowCmd = &cobra.Command{
+	Use:   "show <selector>",
+	Short: "Show the DKIM key record for a selector",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		selector := args[0]
+		return showTagRecord(func(domain string) string { return emailauth.DKIMName(selector, domain) },
+			func(string) bool { return true }, emailauth.ParseDKIM, "DKIM")
+	},
+}
+
+var emailDKIMAddCmd = &cobra.Command{
+	Use:   "add <selector>",
+	Short: "Publish a DKIM public key",
+	Long: `Publish the DKIM key record for a selector from a PEM public key file
+(RSA or Ed25519). An existing record for the selector is replaced.
+
+Examples:
+  openssl rsa -in dkim.key -pubout -out dkim.pub
+  cfcli -d example.com email dkim add mail2026 --key dkim.pub
+  cfcli -d example.com email dkim add mail2026 --key dkim.pub --testing`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dkimKeyFile == "" {
+			return fmt.Errorf("public key file is required (use --key)")
+		}
+		data, err := os.ReadFile(dkimKeyFile)
+		if err != nil {
+			return err
+		}
+		tags, err := emailauth.DKIMFromPublicKey(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", dkimKeyFile, err)
+		}
+		if dkimTesting {
+			tags.Set("t", "y")
+		}
+		if err := emailauth.ValidateDKIM(tags); err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+		domain, err := emailDomain()
+		if err != nil {
+			return err
+		}
+		name := emailauth.DKIMName(args[0], domain)
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		if _, err := selectZone(ctx, client, name); err != nil {
+			return err
+		}
+		record, err := findEmailTXT(ctx, client, name, func(string) bool { return true })
+		if err != nil {
+			return err
+		}
+		return writeEmailTXT(ctx, cmd, client, name, record, tags.String())
+	},
+}
+
+var emailMTASTSCmd = &cobra.Command{
+	Use:   "mta-sts",
+	Short: "Show and update MTA-STS and TLS reporting records",
+}
+
+var emailMTASTSShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the MTA-STS and TLS reporting records",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := showTagRecord(emailauth.MTASTSName, isTag("v=STSv1"), emailauth.ParseMTASTS, "MTA-STS"); err != nil {
+			return err
+		}
+		fmt.Println()
+		return showTagRecord(emailauth.TLSRPTName, isTag("v=TLSRPTv1"), emailauth.ParseTLSRPT, "TLS-RPT")
+	},
+}
+
+var emailMTASTSSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Publish a new MTA-STS policy id and TLS reporting address",
+	Long: `Write the _mta-sts record with a new policy id, so senders refetch the
+policy file, and optionally the _smtp._tls reporting record. The policy file
+itself must be served separately at https://mta-sts.<domain>/.well-known/mta-sts.txt.
+
+Examples:
+  cfcli -d example.com email mta-sts set
+  cfcli -d example.com email mta-sts set --id 2026101901 --rua mailto:tls@example.com`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := mtastsID
+		if id == "" {
+			id = emailauth.NewPolicyID(time.Now())
+		}
+		policy := emailauth.Tags{{Name: "v", Value: "STSv1"}, {Name: "id", Value: id}}
+		if err := emailauth.ValidateMTASTS(policy); err != nil {
+			return err
+		}
+		var report emailauth.Tags
+		if mtastsRUA != "" {
+			report = emailauth.Tags{{Name: "v", Value: "TLSRPTv1"}, {Name: "rua", Value: mtastsRUA}}
+			if err := emailauth.ValidateTLSRPT(report); err != nil {
+				return err
+			}
+		}
+
+		ctx := context.Background()
+		domain, err := emailDomain()
+		if err != nil {
+			return err
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		name := emailauth.MTASTSName(domain)
+		if _, err := selectZone(ctx, client, name); err != nil {
+			return err
+		}
+		record, err := findEmailTXT(ctx, client, name, isTag("v=STSv1"))
+		if err != nil {
+			return err
+		}
+		if err := writeEmailTXT(ctx, cmd, client, name, record, policy.String()); err != nil {
+			return err
+		}
+
+		if report != nil {
+			name := emailauth.TLSRPTName(domain)
+			record, err := findEmailTXT(ctx, client, name, isTag("v=TLSRPTv1"))
+			if err != nil {
+				return err
+			}
+			if err := writeEmailTXT(ctx, cmd, client, name, record, report.String()); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Policy file must be served at %s\n", emailauth.PolicyURL(domain))
+		return nil
+	},
+}
+
+// emailDomain returns the domain whose mail records are managed: --name
+// when given, otherwise the configured domain. A single label given with
+// --name is taken as a subdomain of the configured domain.
+func emailDomain() (string, error) {
+	domain := strings.TrimSuffix(emailName, ".")
+	if domain == "" {
+		domain = cfg.Domain
+	}
+	if cfg.Token == "" {
+		return "", fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+	}
+	if domain == "" {
+		return "", fmt.Errorf("domain is required (use -d, --name or set CF_API_DOMAIN)")
+	}
+	if emailName != "" && cfg.Domain != "" && !strings.Contains(domain, ".") {
+		domain = cloudflare.NormalizeName(domain, cfg.Domain)
+	}
+	return strings.ToLower(domain), nil
+}
+
+// findEmailTXT returns the single TXT record at name whose value matches,
+// or nil when there is none. More than one match is an error, since the
+// policy would then be ambiguous to receivers too.
+func findEmailTXT(ctx context.Context, client *cloudflare.Client, name string, match func(string) bool) (*cloudflare.DNSRecord, error) {
+	records, err := client.FindDNSRecord(ctx, name, "", "TXT")
+	if err != nil {
+		return nil, err
+	}
+	var found []cloudflare.DNSRecord
+	for _, record := range records {
+		if match(dnsquery.UnquoteTXT(record.Content)) {
+			found = append(found, record)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &found[0], nil
+	default:
+		return nil, fmt.Errorf("%d matching TXT records at %s; remove the extras first", len(found), name)
+	}
+}
+
+// writeEmailTXT updates record with content, or creates a TXT record at
+// name when record is nil. Content is quoted with dnsquery.QuoteTXT, so
+// content longer than 255 bytes is split into several strings. With
+// --dry-run the change is only printed.
+func writeEmailTXT(ctx context.Context, cmd *cobra.Command, client *cloudflare.Client, name string, record *cloudflare.DNSRecord, content string) error {
+	if record != nil && dnsquery.UnquoteTXT(record.Content) == content {
+		fmt.Printf("✓ TXT record %s is already up to date\n", name)
+		return nil
+	}
+	if emailDryRun {
+		if record != nil {
+			fmt.Printf("Would update TXT record %s\n  - %s\n  + %s\n", name, dnsquery.UnquoteTXT(record.Content), content)
+		} else {
+			fmt.Printf("Would create TXT record %s\n  + %s\n", name, content)
+		}
+		return nil
+	}
+
+	if record != nil {
+		updated, err := client.UpdateDNSRecord(ctx, record.ID, "TXT", name, dnsquery.QuoteTXT(content), record.TTL, nil, false)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Updated TXT record: %s\n  %s\n", updated.Name, content)
+		return nil
+	}
+	created, err := client.AddDNSRecord(ctx, "TXT", name, dnsquery.QuoteTXT(content), recordTTL(cmd), nil, false)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Created TXT record: %s (ID: %s)\n  %s\n", created.Name, created.ID, content)
+	return nil
+}
+
+// editSPF applies edit to the SPF policy for each term in args and writes
+// the result after validating it.
+func editSPF(cmd *cobra.Command, args []string, edit func(*spf.Record, spf.Term)) error {
+	terms := make([]spf.Term, len(args))
+	for i, arg := range args {
+		t, err := spf.ParseTerm(arg)
+		if err != nil {
+			return err
+		}
+		terms[i] = t
+	}
+
+	ctx := context.Background()
+	name, err := emailDomain()
+	if err != nil {
+		return err
+	}
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	if _, err := selectZone(ctx, client, name); err != nil {
+		return err
+	}
+	record, err := findEmailTXT(ctx, client, name, spf.IsSPF)
+	if err != nil {
+		return err
+	}
+
+	var policy *spf.Record
+	if record != nil {
+		if policy, err = spf.Parse(dnsquery.UnquoteTXT(record.Content)); err != nil {
+			return fmt.Errorf("existing SPF record at %s is invalid: %w", name, err)
+		}
+	} else {
+		policy, _ = spf.Parse("v=spf1 ~all")
+	}
+	for _, t := range terms {
+		edit(policy, t)
+	}
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("refusing to write invalid SPF record: %w", err)
+	}
+
+	lookups, err := spf.CountLookups(ctx, policy, dnsquery.Resolver{Server: emailResolver}.LookupTXT)
+	switch {
+	case err != nil:
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not count DNS lookups: %v\n", err)
+	case lookups > spf.MaxLookups:
+		return fmt.Errorf("refusing to write SPF record needing %d DNS lookups (limit %d): %s", lookups, spf.MaxLookups, policy)
+	}
+	return writeEmailTXT(ctx, cmd, client, name, record, policy.String())
+}
+
+// showTagRecord prints the tag-list record at recordName(domain) that
+// satisfies match, validating it with parse.
+func showTagRecord(recordName func(string) string, match func(string) bool, parse func(string) (emailauth.Tags, error), kind string) error {
+	ctx := context.Background()
+	domain, err := emailDomain()
+	if err != nil {
+		return err
+	}
+	name := recordName(domain)
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+	if _, err := selectZone(ctx, client, name); err != nil {
+		return err
+	}
+	record, err := findEmailTXT(ctx, client, name, match)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("no %s record at %s", kind, name)
+	}
+
+	content := dnsquery.UnquoteTXT(record.Content)
+	tags, parseErr := parse(content)
+	if tags == nil {
+		return fmt.Errorf("%s record at %s is malformed: %w", kind, name, parseErr)
+	}
+
+	if strings.ToLower(format) == "json" {
+		out := struct {
+			Name   string         `json:"name"`
+			Record string         `json:"record"`
+			Tags   emailauth.Tags `json:"tags"`
+			Valid  bool           `json:"valid"`
+			Error  string         `json:"error,omitempty"`
+		}{Name: name, Record: content, Tags: tags, Valid: parseErr == nil}
+		if parseErr != nil {
+			out.Error = parseErr.Error()
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	}
+
+	fmt.Printf("%s\n", name)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Tag", "Value")
+	for _, tag := range tags {
+		value := tag.Value
+		if len(value) > 60 {
+			value = value[:28] + "..." + value[len(value)-28:]
+		}
+		if err := table.Append(tag.Name, value); err != nil {
+			return err
+		}
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+	if parseErr != nil {
+		fmt.Printf("✗ %v\n", parseErr)
+	}
+	return nil
+}
+
+func isTag(prefix string) func(string) bool {
+	return func(txt string) bool {
+		return strings.HasPrefix(strings.TrimSpace(txt), prefix)
+	}
+}
+
+// spfSourcePrefix names the record holding the unflattened SPF policy;
+// spfSourceTag is prepended to its content so that it is not itself
+// mistaken for an SPF policy.
+const (
+	spfSourcePrefix = "_spf-source."
+	spfSourceTag    = "source "
+)
+
+// isPartName reports whether name is one of the _spfN records of a
+// flattened policy for domain.
+func isPartName(name, domain string) bool {
+	label, ok := strings.CutSuffix(strings.ToLower(name), "."+domain)
+	if !ok {
+		return false
+	}
+	n, ok := strings.CutPrefix(label, "_spf")
+	if !ok || n == "" {
+		return false
+	}
+	return strings.Trim(n, "0123456789") == ""
+}
+
+// singleTXT returns the TXT record at name among records whose value
+// matches, or nil. More than one match is an error.
+func singleTXT(records []cloudflare.DNSRecord, name string, match func(string) bool) (*cloudflare.DNSRecord, error) {
+	var found *cloudflare.DNSRecord
+	for i, r := range records {
+		if !strings.EqualFold(r.Type, "TXT") || !strings.EqualFold(r.Name, name) || !match(dnsquery.UnquoteTXT(r.Content)) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("more than one matching TXT record at %s; remove the extras first", name)
+		}
+		found = &records[i]
+	}
+	return found, nil
+}
+
+func reportNetworkChanges(before, after map[string]bool) {
+	var added, removed []string
+	for n := range after {
+		if !before[n] {
+			added = append(added, n)
+		}
+	}
+	for n := range before {
+		if !after[n] {
+			removed = append(removed, n)
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		fmt.Println("No range changes")
+		return
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	for _, n := range added {
+		fmt.Printf("  + %s\n", n)
+	}
+	for _, n := range removed {
+		fmt.Printf("  - %s\n", n)
+	}
+}
+
+// spfResult describes what a mechanism's qualifier does on a match.
+func spfResult(t spf.Term) string {
+	if t.Modifier {
+		return "modifier"
+	}
+	switch t.Qualifier {
+	case '-':
+		return "fail"
+	case '~':
+		return "softfail"
+	case '?':
+		return "neutral"
+	default:
+		return "pass"
+	}
+}
+
+func init() {
+	emailCmd.PersistentFlags().StringVar(&emailName, "name", "", "Domain to manage mail records for (default: the configured domain)")
+	emailCmd.PersistentFlags().BoolVar(&emailDryRun, "dry-run", false, "Show the change without writing it")
+	emailCmd.PersistentFlags().StringVar(&emailResolver, "resolver", "1.1.1.1", "Resolver used to count SPF DNS lookups")
+
+	emailDMARCSetCmd.Flags().StringSliceVar(&emailUnset, "unset", nil, "Tags to remove")
+	emailDKIMAddCmd.Flags().StringVar(&dkimKeyFile, "key", "", "PEM public key file")
+	emailDKIMAddCmd.Flags().BoolVar(&dkimTesting, "testing", false, "Mark the key as in testing (t=y)")
+	emailMTASTSSetCmd.Flags().StringVar(&mtastsID, "id", "", "Policy id (default: the current UTC time)")
+	emailMTASTSSetCmd.Flags().StringVar(&mtastsRUA, "rua", "", "TLS report destination (mailto: or https: URI)")
+
+	emailSPFCmd.AddCommand(emailSPFShowCmd, emailSPFAddCmd, emailSPFRemoveCmd, emailSPFFlattenCmd)
+	emailDMARCCmd.AddCommand(emailDMARCShowCmd, emailDMARCSetCmd)
+	emailDKIMCmd.AddCommand(emailDKIMShowCmd, emailDKIMAddCmd)
+	emailMTASTSCmd.AddCommand(emailMTASTSShowCmd, emailMTASTSSetCmd)
+	emailCmd.AddCommand(emailSPFCmd, emailDMARCCmd, emailDKIMCmd, emailMTASTSCmd)
+	rootCmd.AddCommand(emailCmd)
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/dnsquery"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintMinSeverity string
+	lintFailOn      string
+	lintResolver    string
+	lintOffline     bool
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Audit the zone's records for problems",
+	Long: `Analyze the zone's DNS records and report problems:
+
+  duplicate            identical records
+  dangling-cname       CNAMEs to names that do not exist
+  subdomain-takeover   CNAMEs to deprovisioned cloud/hosting endpoints
+  target-is-cname      MX or NS records pointing at a CNAME
+  spf-multiple         more than one SPF record on a name
+  spf-syntax           SPF records that do not parse
+  spf-lookups          SPF policies over the 10 DNS lookup limit
+  dmarc                missing or monitoring-only DMARC policy
+  origin-exposed       unproxied records revealing a proxied origin IP
+  private-address      RFC 1918 and other non-public addresses
+
+Exits with status 1 when a finding at or above --fail-on is reported.
+
+Examples:
+  cfcli -d example.com lint
+  cfcli -d example.com lint --min-severity warning
+  cfcli -d example.com -f json lint --fail-on warning`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Token == "" {
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+		}
+		if cfg.Domain == "" {
+			return fmt.Errorf("domain is required (use -d or set CF_API_DOMAIN)")
+		}
+		minSeverity, err := lint.ParseSeverity(lintMinSeverity)
+		if err != nil {
+			return err
+		}
+		failOn := lint.Severity(-1)
+		if !strings.EqualFold(lintFailOn, "none") {
+			if failOn, err = lint.ParseSeverity(lintFailOn); err != nil {
+				return err
+			}
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+
+		if err := client.SetZone(ctx, cfg.Domain); err != nil {
+			return err
+		}
+		records, err := client.ListDNSRecords(ctx)
+		if err != nil {
+			return err
+		}
+
+		linter := &lint.Linter{Zone: client.ZoneName(), Records: records}
+		if !lintOffline {
+			linter.Resolver = dnsquery.Resolver{Server: lintResolver}
+		}
+
+		var findings []lint.Finding
+		failed := false
+		for _, f := range linter.Run(ctx) {
+			if f.Severity >= minSeverity {
+				findings = append(findings, f)
+			}
+			if failOn >= 0 && f.Severity >= failOn {
+				failed = true
+			}
+		}
+
+		if err := outputFindings(findings); err != nil {
+			return err
+		}
+		if failed {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("lint found problems at or above %s severity", failOn)
+		}
+		return nil
+	},
+}
+
+func outputFindings(findings []lint.Finding) error {
+	if strings.ToLower(format) == "json" {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(findings)
+	}
+
+	if len(findings) == 0 {
+		fmt.Println("✓ No problems found")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Severity", "Check", "Name", "Type", "Message")
+	for _, f := range findings {
+		if err := table.Append(f.Severity.String(), f.Check, f.Name, f.Type, f.Message); err != nil {
+			return err
+		}
+	}
+	return table.Render()
+}
+
+func init() {
+	lintCmd.Flags().StringVar(&lintMinSeverity, "min-severity", "info", "Only report findings at or above this severity: info, warning, error")
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", "error", "Exit non-zero on findings at or above this severity (or none)")
+	lintCmd.Flags().StringVar(&lintResolver, "resolver", "1.1.1.1", "Resolver used for checks that look outside the zone")
+	lintCmd.Flags().BoolVar(&lintOffline, "offline", false, "Skip checks that need live DNS lookups")
+	rootCmd.AddCommand(lintCmd)
+}
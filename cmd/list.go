@@ -20,13 +20,13 @@ var listCmd = &cobra.Command{
 	Short:   "List DNS records for the domain",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if cfg.Token == "" {
-			return fmt.Errorf("API token is required (use -k or set CF_API_KEY)")
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
 		}
 		if cfg.Domain == "" {
 			return fmt.Errorf("domain is required (use -d or set CF_API_DOMAIN)")
 		}
 
-		client, err := cloudflare.NewClient(cfg.Token, cfg.Email)
+		client, err := newClient()
 		if err != nil {
 			return err
 		}
@@ -71,7 +71,7 @@ func filterRecords(records []cloudflare.DNSRecord) []cloudflare.DNSRecord {
 					match = false
 				}
 			case "name":
-				if !strings.Contains(strings.ToLower(record.Name), strings.ToLower(value)) {
+				if !strings.Contains(strings.ToLower(record.Name), strings.ToLower(strings.TrimSuffix(value, "."))) {
 					match = false
 				}
 			case "content":
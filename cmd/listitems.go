@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/spf13/cobra"
+)
+
+// syncListItems brings a list holding existing in line by removing and
+// adding items. Items that change are removed and added under the same
+// key; when there are any, the whole list is replaced in one operation so
+// that they are never missing. Otherwise removals and then additions are
+// sent in batches of size, each waited on. On failure the keys that were
+// not applied are listed on stderr.
+func syncListItems(ctx context.Context, cmd *cobra.Command, client *cloudflare.Client, accountID, listID string, existing, add, remove []cloudflare.ListItem, size int) error {
+	removed := make(map[string]bool, len(remove))
+	for _, item := range remove {
+		removed[item.Key()] = true
+	}
+	if slices.ContainsFunc(add, func(item cloudflare.ListItem) bool { return removed[item.Key()] }) {
+		items := make([]cloudflare.ListItem, 0, len(existing)+len(add))
+		for _, item := range existing {
+			if !removed[item.Key()] {
+				items = append(items, item)
+			}
+		}
+		items = append(items, add...)
+		if err := client.ReplaceListItems(ctx, accountID, listID, items); err != nil {
+			reportNotApplied(cmd, "removed", remove)
+			reportNotApplied(cmd, "added", add)
+			return fmt.Errorf("replacing the list's items: %w", err)
+		}
+		fmt.Printf("  replaced the list's items (%d)\n", len(items))
+		return nil
+	}
+
+	if size <= 0 {
+		size = cloudflare.ListBatchSize
+	}
+	for done := 0; done < len(remove); {
+		n := min(size, len(remove)-done)
+		ids := make([]string, n)
+		for i, item := range remove[done : done+n] {
+			ids[i] = item.ID
+		}
+		if err := client.DeleteListItems(ctx, accountID, listID, ids); err != nil {
+			reportNotApplied(cmd, "removed", remove[done:])
+			reportNotApplied(cmd, "added", add)
+			return fmt.Errorf("removing items %d-%d of %d: %w", done+1, done+n, len(remove), err)
+		}
+		done += n
+		fmt.Printf("  removed %d/%d\n", done, len(remove))
+	}
+	for done := 0; done < len(add); {
+		n := min(size, len(add)-done)
+		if err := client.AddListItems(ctx, accountID, listID, add[done:done+n]); err != nil {
+			reportNotApplied(cmd, "added", add[done:])
+			return fmt.Errorf("adding items %d-%d of %d: %w", done+1, done+n, len(add), err)
+		}
+		done += n
+		fmt.Printf("  added %d/%d\n", done, len(add))
+	}
+	return nil
+}
+
+// reportNotApplied lists on stderr the keys of items that were not added
+// or removed.
+func reportNotApplied(cmd *cobra.Command, verb string, items []cloudflare.ListItem) {
+	for _, item := range items {
+		fmt.Fprintf(cmd.ErrOrStderr(), "not %s: %s\n", verb, item.Key())
+	}
+}
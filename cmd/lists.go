@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/iplist"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listsAccountID   string
+	listsDescription string
+	listsFile        string
+	listsComment     string
+	listsSkipInvalid bool
+	listsDryRun      bool
+	listsAllowEmpty  bool
+	listsBatchSize   int
+)
+
+var listNameRE = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+var listsCmd = &cobra.Command{
+	Use:   "lists",
+	Short: "List, create and sync account IP lists",
+	Long: `List the account's lists, create IP lists and keep them in sync with a
+file of IP addresses and CIDR ranges.
+
+IP lists are used from WAF custom rules, e.g. 'ip.src in $blocklist'.
+
+Examples:
+  cfcli lists
+  cfcli lists create blocklist --description "Threat intel"
+  cfcli lists sync blocklist -f ips.txt --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, accountID, err := accountClient(listsAccountID)
+		if err != nil {
+			return err
+		}
+		lists, err := client.Lists(context.Background(), accountID)
+		if err != nil {
+			return err
+		}
+
+		if strings.ToLower(format) == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(lists)
+		}
+		if len(lists) == 0 {
+			fmt.Println("No lists found")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Name", "Kind", "Items", "Description", "ID")
+		for _, l := range lists {
+			if err := table.Append(l.Name, l.Kind, strconv.Itoa(l.NumItems), orDash(l.Description), l.ID); err != nil {
+				return err
+			}
+		}
+		return table.Render()
+	},
+}
+
+var listsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an empty IP list",
+	Long: `Create an empty IP list in the account. Names may only contain lowercase
+letters, digits and underscores.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !listNameRE.MatchString(args[0]) {
+			return fmt.Errorf("invalid list name %q (use lowercase letters, digits and underscores)", args[0])
+		}
+		client, accountID, err := accountClient(listsAccountID)
+		if err != nil {
+			return err
+		}
+		list, err := client.CreateList(context.Background(), accountID, args[0], cloudflare.ListKindIP, listsDescription)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Created IP list %s (%s)\n", list.Name, list.ID)
+		return nil
+	},
+}
+
+var listsItemsCmd = &cobra.Command{
+	Use:   "items <name>",
+	Short: "Show the items of an IP list",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, accountID, err := accountClient(listsAccountID)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		list, err := ipList(ctx, client, accountID, args[0])
+		if err != nil {
+			return err
+		}
+		items, err := client.ListItems(ctx, accountID, list.ID)
+		if err != nil {
+			return err
+		}
+
+		if strings.ToLower(format) == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(items)
+		}
+		for _, item := range items {
+			if item.Comment != "" {
+				fmt.Printf("%s  # %s\n", item.IP, item.Comment)
+			} else {
+				fmt.Println(item.IP)
+			}
+		}
+		return nil
+	},
+}
+
+var listsSyncCmd = &cobra.Command{
+	Use:   "sync <name> -f <file>",
+	Short: "Make an IP list match a file of IPs and CIDR ranges",
+	Long: `Make an IP list match a file ("-" for stdin) with one IP address or CIDR
+range per line. Blank lines and text after # are ignored; IPv4 ranges may be
+/8 to /32 and IPv6 ranges /12 to /64.
+
+Only the differences are sent: addresses missing from the list are added
+and addresses no longer in the file are removed, in batches that are each
+waited on until the API has processed them. Comments on items already in
+the list are kept. An empty file is refused unless --allow-empty is given.
+
+Examples:
+  cfcli lists sync blocklist -f ips.txt --dry-run
+  curl -s https://intel.example.com/feed.txt | cfcli lists sync blocklist -f - --comment "feed $(date +%F)"`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, data, err := readInputFile(cmd, args[1:], listsFile)
+		if err != nil {
+			return err
+		}
+		ips, lineErrs, err := iplist.Read(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		for _, e := range lineErrs {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", path, e)
+		}
+		if len(lineErrs) > 0 {
+			if !listsSkipInvalid {
+				return fmt.Errorf("%d invalid line(s) in %s; fix them or use --skip-invalid", len(lineErrs), path)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipping %d invalid line(s)\n", len(lineErrs))
+		}
+		if len(ips) == 0 && !listsAllowEmpty {
+			// Most likely a failed download rather than an empty feed.
+			return fmt.Errorf("no IPs in %s; use --allow-empty to remove every item from the list", path)
+		}
+
+		client, accountID, err := accountClient(listsAccountID)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		list, err := ipList(ctx, client, accountID, args[0])
+		if err != nil {
+			return err
+		}
+		existing, err := client.ListItems(ctx, accountID, list.ID)
+		if err != nil {
+			return err
+		}
+
+		// Compare addresses only, so that comments on existing items are
+		// neither compared nor lost.
+		current := make([]cloudflare.ListItem, len(existing))
+		for i, item := range existing {
+			current[i] = cloudflare.ListItem{ID: item.ID, IP: item.IP}
+			if ip, err := iplist.Normalize(item.IP); err == nil {
+				current[i].IP = ip
+			}
+		}
+		desired := make([]cloudflare.ListItem, len(ips))
+		for i, ip := range ips {
+			desired[i] = cloudflare.ListItem{IP: ip}
+		}
+		add, remove := cloudflare.DiffListItems(current, desired, true)
+		for i := range add {
+			add[i].Comment = listsComment
+		}
+
+		if len(add) == 0 && len(remove) == 0 {
+			fmt.Printf("✓ List %s already matches %s (%d items)\n", list.Name, path, len(ips))
+			return nil
+		}
+		if listsDryRun {
+			for _, item := range add {
+				fmt.Printf("+ %s\n", item.IP)
+			}
+			for _, item := range remove {
+				fmt.Printf("- %s\n", item.IP)
+			}
+			fmt.Printf("Would add %d and remove %d item(s)\n", len(add), len(remove))
+			return nil
+		}
+		if err := syncListItems(ctx, cmd, client, accountID, list.ID, existing, add, remove, listsBatchSize); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Synced list %s with %s: %d added, %d removed, %d items\n", list.Name, path, len(add), len(remove), len(ips))
+		return nil
+	},
+}
+
+// ipList returns the account's IP list with the given name.
+func ipList(ctx context.Context, client *cloudflare.Client, accountID, name string) (*cloudflare.List, error) {
+	list, err := client.ListByName(ctx, accountID, name)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case list == nil:
+		return nil, fmt.Errorf("no list named %s in the account (create it with 'cfcli lists create %s')", name, name)
+	case list.Kind != cloudflare.ListKindIP:
+		return nil, fmt.Errorf("list %s is a %s list, not an IP list", list.Name, list.Kind)
+	}
+	return list, nil
+}
+
+func init() {
+	listsCmd.PersistentFlags().StringVar(&listsAccountID, "account-id", "", "Cloudflare account ID (default: the token's only account)")
+	listsCreateCmd.Flags().StringVar(&listsDescription, "description", "", "List description")
+	listsSyncCmd.Flags().StringVar(&listsFile, "file", "", `File of IPs and CIDR ranges ("-" for stdin)`)
+	listsSyncCmd.Flags().StringVar(&listsComment, "comment", "", "Comment for added items")
+	listsSyncCmd.Flags().BoolVar(&listsSkipInvalid, "skip-invalid", false, "Sync the valid lines when some are invalid")
+	listsSyncCmd.Flags().BoolVar(&listsDryRun, "dry-run", false, "Show the additions and removals without making them")
+	listsSyncCmd.Flags().BoolVar(&listsAllowEmpty, "allow-empty", false, "Allow an empty file, removing every item")
+	listsSyncCmd.Flags().IntVar(&listsBatchSize, "batch-size", cloudflare.ListBatchSize, "Items per API request")
+
+	listsCmd.AddCommand(listsCreateCmd, listsItemsCmd, listsSyncCmd)
+	rootCmd.AddCommand(listsCmd)
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/redirect"
+	"github.com/spf13/cobra"
+)
+
+var (
+	redirectsList        string
+	redirectsAccountID   string
+	redirectsDescription string
+	redirectsPrune       bool
+	redirectsSkipInvalid bool
+	redirectsDryRun      bool
+	redirectsBatchSize   int
+)
+
+var redirectsCmd = &cobra.Command{
+	Use:   "redirects",
+	Short: "Import and export bulk redirect lists",
+	Long: `Manage account-level bulk redirect lists as CSV files.
+
+A list only takes effect once a Bulk Redirect rule in the account refers to
+it; create the rule once in the dashboard, then keep the list up to date with
+'redirects import'.`,
+}
+
+var redirectsImportCmd = &cobra.Command{
+	Use:   "import <file.csv> --list <name>",
+	Short: "Create or update a redirect list from a CSV file",
+	Long: `Create or update a redirect list from a CSV file ("-" for stdin).
+
+Each row is one redirect. The columns are, in order:
+
+  source_url             host and path, e.g. example.com/old
+  target_url             absolute URL to redirect to
+  status_code            301 (default), 302, 307 or 308
+  include_subdomains     true or false (default)
+  subpath_matching       true or false (default)
+  preserve_query_string  true or false (default)
+  preserve_path_suffix   true or false (default), needs subpath_matching
+  comment
+
+Trailing columns may be left out. A header row naming the columns allows
+any order, and is written by 'redirects export'.
+
+The list is created if it does not exist. Rows whose source URL is already
+in the list are replaced when they changed, in a single operation that
+replaces the whole list so that no redirect is ever missing; items missing
+from the file are kept unless --prune is given. Every row is checked before anything is
+uploaded, and invalid rows stop the import unless --skip-invalid is given.
+
+Examples:
+  cfcli redirects import redirects.csv --list legacy --dry-run
+  cfcli redirects import redirects.csv --list legacy --prune`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		items, err := readRedirectsCSV(cmd, args[0])
+		if err != nil {
+			return err
+		}
+
+		client, accountID, err := accountClient(redirectsAccountID)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		list, err := redirectList(ctx, client, accountID)
+		if err != nil {
+			return err
+		}
+
+		var existing []cloudflare.ListItem
+		if list != nil {
+			if existing, err = client.ListItems(ctx, accountID, list.ID); err != nil {
+				return err
+			}
+		}
+		add, remove := cloudflare.DiffListItems(existing, items, redirectsPrune)
+		unchanged := len(items) - len(add)
+		if len(add) == 0 && len(remove) == 0 {
+			fmt.Printf("✓ List %s already matches %s (%d redirects)\n", redirectsList, args[0], unchanged)
+			return nil
+		}
+
+		if redirectsDryRun {
+			if list == nil {
+				fmt.Printf("Would create redirect list %s\n", redirectsList)
+			}
+			fmt.Printf("Would add %d and remove %d redirect(s) (%d unchanged)\n", len(add), len(remove), unchanged)
+			return nil
+		}
+
+		if list == nil {
+			if list, err = client.CreateList(ctx, accountID, redirectsList, cloudflare.ListKindRedirect, redirectsDescription); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Created redirect list %s; refer to it from a Bulk Redirect rule to enable it\n", list.Name)
+		}
+		if err := syncListItems(ctx, cmd, client, accountID, list.ID, existing, add, remove, redirectsBatchSize); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Imported %s into list %s: %d added, %d removed, %d unchanged\n", args[0], list.Name, len(add), len(remove), unchanged)
+		return nil
+	},
+}
+
+var redirectsExportCmd = &cobra.Command{
+	Use:   "export --list <name> [file.csv]",
+	Short: "Write a redirect list to a CSV file",
+	Long: `Write a redirect list to a CSV file, or to stdout when no file is given,
+in the format 'redirects import' reads.
+
+Examples:
+  cfcli redirects export --list legacy > redirects.csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, accountID, err := accountClient(redirectsAccountID)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		list, err := redirectList(ctx, client, accountID)
+		if err != nil {
+			return err
+		}
+		if list == nil {
+			return fmt.Errorf("no list named %s in the account", redirectsList)
+		}
+		items, err := client.ListItems(ctx, accountID, list.ID)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if len(args) == 1 && args[0] != "-" {
+			f, err := os.Create(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := redirect.WriteCSV(w, items); err != nil {
+			return err
+		}
+		if w != os.Stdout {
+			fmt.Fprintf(cmd.ErrOrStderr(), "✓ Exported %d redirect(s) from list %s to %s\n", len(items), list.Name, args[0])
+		}
+		return nil
+	},
+}
+
+// readRedirectsCSV reads and checks the redirects in path, or stdin for
+// "-", reporting each invalid row on stderr.
+func readRedirectsCSV(cmd *cobra.Command, path string) ([]cloudflare.ListItem, error) {
+	var r io.Reader = cmd.InOrStdin()
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	items, rowErrs := redirect.ReadCSV(r)
+	for _, e := range rowErrs {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s: %v\n", path, e)
+	}
+	switch {
+	case len(rowErrs) > 0 && !redirectsSkipInvalid:
+		return nil, fmt.Errorf("%d invalid row(s) in %s; fix them or use --skip-invalid", len(rowErrs), path)
+	case len(items) == 0:
+		return nil, fmt.Errorf("no redirects in %s", path)
+	case len(rowErrs) > 0:
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipping %d invalid row(s)\n", len(rowErrs))
+	}
+	return items, nil
+}
+
+// redirectList returns the list named by --list, or nil if the account has
+// none by that name.
+func redirectList(ctx context.Context, client *cloudflare.Client, accountID string) (*cloudflare.List, error) {
+	list, err := client.ListByName(ctx, accountID, redirectsList)
+	if err != nil {
+		return nil, err
+	}
+	if list != nil && list.Kind != cloudflare.ListKindRedirect {
+		return nil, fmt.Errorf("list %s is a %s list, not a redirect list", list.Name, list.Kind)
+	}
+	return list, nil
+}
+
+func init() {
+	redirectsCmd.PersistentFlags().StringVar(&redirectsList, "list", "", "Name of the redirect list")
+	redirectsCmd.PersistentFlags().StringVar(&redirectsAccountID, "account-id", "", "Cloudflare account ID (default: the token's only account)")
+	_ = redirectsCmd.MarkPersistentFlagRequired("list")
+
+	redirectsImportCmd.Flags().StringVar(&redirectsDescription, "description", "", "Description for a newly created list")
+	redirectsImportCmd.Flags().BoolVar(&redirectsPrune, "prune", false, "Remove redirects that are not in the file")
+	redirectsImportCmd.Flags().BoolVar(&redirectsSkipInvalid, "skip-invalid", false, "Import the valid rows when some are invalid")
+	redirectsImportCmd.Flags().BoolVar(&redirectsDryRun, "dry-run", false, "Show what would change without changing it")
+	redirectsImportCmd.Flags().IntVar(&redirectsBatchSize, "batch-size", cloudflare.ListBatchSize, "Items per API request")
+
+	redirectsCmd.AddCommand(redirectsImportCmd, redirectsExportCmd)
+	rootCmd.AddCommand(redirectsCmd)
+}
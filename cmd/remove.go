@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
 	"github.com/spf13/cobra"
 )
 
@@ -21,10 +20,7 @@ Examples:
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if cfg.Token == "" {
-			return fmt.Errorf("API token is required (use -k or set CF_API_KEY)")
-		}
-		if cfg.Domain == "" {
-			return fmt.Errorf("domain is required (use -d or set CF_API_DOMAIN)")
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
 		}
 
 		name := args[0]
@@ -39,13 +35,14 @@ Examples:
 			filters["content"] = content
 		}
 
-		client, err := cloudflare.NewClient(cfg.Token, cfg.Email)
+		client, err := newClient()
 		if err != nil {
 			return err
 		}
 		ctx := context.Background()
 
-		if err := client.SetZone(ctx, cfg.Domain); err != nil {
+		name, err = selectZone(ctx, client, name)
+		if err != nil {
 			return err
 		}
 
@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cache"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
 	"github.com/rjshrjndrn/cloudflare-cli/internal/config"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +28,7 @@ var (
 	activate   bool
 	format     string
 	query      string
+	noCache    bool
 
 	cfg *config.Config
 )
@@ -62,28 +70,153 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&recordType, "type", "t", "", "Type of DNS record (A, AAAA, CNAME, MX, TXT, NS, SRV)")
 	rootCmd.PersistentFlags().StringVarP(&newType, "newtype", "n", "", "New type when editing a record")
 	rootCmd.PersistentFlags().Int64VarP(&priority, "priority", "p", 0, "Priority for MX or SRV records")
-	rootCmd.PersistentFlags().Int64VarP(&ttl, "ttl", "l", 1, "TTL in seconds (1 for auto, 120-86400)")
+	rootCmd.PersistentFlags().Int64VarP(&ttl, "ttl", "l", 1, "TTL in seconds (1 for auto, 60-86400)")
 	rootCmd.PersistentFlags().BoolVarP(&activate, "activate", "a", false, "Activate cloudflare (enable proxy) after creating record")
 	rootCmd.PersistentFlags().StringVarP(&format, "format", "f", "table", "Output format: table, json, csv")
 	rootCmd.PersistentFlags().StringVarP(&query, "query", "q", "", "Comma-separated filters (e.g., content:1.1.1.1,type:A)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not use or update the on-disk zone cache")
 }
 
-func initConfig() {
-	var err error
-	cfg, err = config.LoadConfig(cfgFile, account)
+// newClient creates an API client for the effective credentials, with the
+// on-disk zone cache enabled unless --no-cache was given.
+func newClient() (*cloudflare.Client, error) {
+	return clientFor(cfg)
+}
+
+// clientFor creates an API client for the credentials in c.
+func clientFor(c *config.Config) (*cloudflare.Client, error) {
+	client, err := cloudflare.NewClient(c.Token, c.Email)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Could not load config file: %v\n", err)
-		cfg = &config.Config{}
+		return nil, err
+	}
+	if !noCache {
+		if store, err := cache.New(cache.Key(c.Account, c.Token), cache.DefaultTTL); err == nil {
+			client.SetCache(store)
+		}
+	}
+	return client, nil
+}
+
+// domainClient returns a client with the configured domain's zone
+// selected.
+func domainClient() (*cloudflare.Client, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+	}
+	if cfg.Domain == "" {
+		return nil, fmt.Errorf("domain is required (use -d or set CF_API_DOMAIN)")
+	}
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SetZone(context.Background(), cfg.Domain); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// accountClient returns a client and the ID of the account to work on:
+// accountID, or the token's only account when it is empty.
+func accountClient(accountID string) (*cloudflare.Client, string, error) {
+	if cfg.Token == "" {
+		return nil, "", fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+	}
+	client, err := newClient()
+	if err != nil {
+		return nil, "", err
+	}
+	accountID, err = client.ResolveAccountID(context.Background(), accountID)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, accountID, nil
+}
+
+// prompt prints question and returns the line typed in reply, trimmed.
+func prompt(cmd *cobra.Command, question string) (string, error) {
+	fmt.Fprint(cmd.OutOrStdout(), question)
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// confirm asks a yes/no question on stdin. Anything but "y" or "yes" is
+// taken as no.
+func confirm(cmd *cobra.Command, question string) (bool, error) {
+	answer, err := prompt(cmd, question+" [y/N] ")
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// recordTTL returns the TTL for new or edited records: the --ttl flag when
+// given, otherwise the default configured for the domain.
+func recordTTL(cmd *cobra.Command) int {
+	if !cmd.Flags().Changed("ttl") && cfg.TTL > 0 {
+		return cfg.TTL
+	}
+	return int(ttl)
+}
+
+// recordProxied returns whether new records should be proxied: the
+// --activate flag when given, otherwise the default configured for the
+// domain.
+func recordProxied(cmd *cobra.Command) bool {
+	if !cmd.Flags().Changed("activate") && cfg.Proxied != nil {
+		return *cfg.Proxied
+	}
+	return activate
+}
+
+// selectZone selects the zone holding the record name and returns the name
+// in fully-qualified form. The configured domain is used for relative
+// names and names inside it. Names ending in a dot, names given without a
+// domain, and dotted names outside a domain that was not passed with -d are
+// matched against the account's zones by longest suffix instead.
+func selectZone(ctx context.Context, client *cloudflare.Client, name string) (string, error) {
+	absolute := strings.HasSuffix(name, ".")
+	if cfg.Domain != "" && !absolute {
+		explicit := cfg.Origin("domain").Source == config.SourceFlag
+		dotted := strings.Contains(name, ".")
+		if explicit || !dotted || cloudflare.InZone(name, cfg.Domain) {
+			if err := client.SetZone(ctx, cfg.Domain); err != nil {
+				return "", err
+			}
+			return cloudflare.NormalizeName(name, cfg.Domain), nil
+		}
 	}
 
-	// Override config with command-line flags
-	if token != "" {
-		cfg.Token = token
+	if cfg.Domain == "" && (name == "@" || !strings.Contains(strings.TrimSuffix(name, "."), ".")) {
+		return "", fmt.Errorf("domain is required for %q (use -d, set CF_API_DOMAIN or give a fully-qualified name)", name)
 	}
-	if email != "" {
-		cfg.Email = email
+
+	zone, err := client.SetZoneForName(ctx, name)
+	if err != nil {
+		if cfg.Domain != "" && !absolute {
+			// Not in any zone we can see; treat it as relative after all.
+			if err := client.SetZone(ctx, cfg.Domain); err != nil {
+				return "", err
+			}
+			return cloudflare.NormalizeName(name, cfg.Domain), nil
+		}
+		return "", err
 	}
-	if domain != "" {
-		cfg.Domain = domain
+	return cloudflare.NormalizeName(name, zone.Name), nil
+}
+
+func initConfig() {
+	var err error
+	cfg, err = config.LoadConfig(cfgFile, account, map[string]string{
+		"token":  token,
+		"email":  email,
+		"domain": domain,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Could not load config file: %v\n", err)
 	}
 }
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/redirect"
+	"github.com/spf13/cobra"
+)
+
+var (
+	redirectPreserveQuery bool
+	redirectDescription   string
+	redirectDryRun        bool
+	redirectMigrateAll    bool
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage redirect rules and list page rules",
+}
+
+var rulesRedirectCmd = &cobra.Command{
+	Use:   "redirect",
+	Short: "Manage single redirect rules",
+	Long: `Manage single redirect rules (the http_request_dynamic_redirect phase of
+the Rulesets API). Rules are written as "from → to [status]":
+
+  from    host and optional path, e.g. example.com/old or *.example.com/blog/*;
+          prefix with http:// or https:// to match that scheme only
+  to      absolute URL; ${1}, ${2}, ... are replaced by what the *s in the
+          path of from matched
+  status  301 (default), 302, 303, 307 or 308
+
+The first matching rule wins.`,
+}
+
+var rulesRedirectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the zone's redirect rules in evaluation order",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		rules, err := client.RedirectRules(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if strings.ToLower(format) == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(rules)
+		}
+		if len(rules) == 0 {
+			fmt.Println("No redirect rules found")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("#", "Description", "Expression", "Target", "Code", "Enabled", "ID")
+		for i, r := range rules {
+			target := r.TargetURL
+			if target == "" {
+				target = r.TargetExpression
+			}
+			if r.PreserveQuery {
+				target += " (+query)"
+			}
+			enabled := " "
+			if r.Enabled {
+				enabled = "✓"
+			}
+			if err := table.Append(strconv.Itoa(i+1), orDash(r.Description), r.Expression, target, strconv.Itoa(r.StatusCode), enabled, r.ID); err != nil {
+				return err
+			}
+		}
+		return table.Render()
+	},
+}
+
+var rulesRedirectAddCmd = &cobra.Command{
+	Use:   "add <from> → <to> [status]",
+	Short: "Add a redirect rule",
+	Long: `Add a redirect rule at the end of the list. The arrow may be written as
+→ or ->, or left out; quote the whole spec when using ->, which would
+otherwise be read as a flag.
+
+Examples:
+  cfcli -d example.com rules redirect add example.com/old → https://example.com/new
+  cfcli -d example.com rules redirect add 'www.example.com/blog/* -> https://blog.example.com/${1} 308'
+  cfcli -d example.com rules redirect add example.com/promo https://shop.example.com/ 302 --preserve-query`,
+	Args: cobra.RangeArgs(1, 4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec, err := redirect.ParseSpec(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		spec.PreserveQuery = redirectPreserveQuery
+		rule, err := redirectRule(spec, redirectDescription)
+		if err != nil {
+			return err
+		}
+
+		if redirectDryRun {
+			printRedirectRule("Would add", rule)
+			return nil
+		}
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		if err := client.AddRedirectRules(context.Background(), rule); err != nil {
+			return err
+		}
+		printRedirectRule("✓ Added", rule)
+		return nil
+	},
+}
+
+var rulesRedirectRemoveCmd = &cobra.Command{
+	Use:     "rm <id|#>",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Remove a redirect rule by ID or list position",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		rules, err := client.RedirectRules(ctx)
+		if err != nil {
+			return err
+		}
+
+		var rule *cloudflare.RedirectRule
+		if n, err := strconv.Atoi(args[0]); err == nil && n >= 1 && n <= len(rules) {
+			rule = &rules[n-1]
+		} else {
+			for i := range rules {
+				if rules[i].ID == args[0] {
+					rule = &rules[i]
+				}
+			}
+		}
+		if rule == nil {
+			return fmt.Errorf("no redirect rule %s (see 'cfcli rules redirect list')", args[0])
+		}
+
+		if err := client.DeleteRedirectRule(ctx, rule.ID); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Removed redirect rule %s (%s)\n", rule.ID, orDash(rule.Description))
+		return nil
+	},
+}
+
+var rulesRedirectMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Convert forwarding page rules into redirect rules",
+	Long: `Convert the zone's forwarding URL page rules into single redirect rules,
+in page rule priority order, preserving query strings as page rules do.
+Page rules that were already migrated are skipped, and page rules are left
+unchanged: once the redirect rules work as expected, disable or delete the
+page rules in the dashboard.
+
+Only active page rules are converted unless --all is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		pageRules, err := client.PageRules(ctx)
+		if err != nil {
+			return err
+		}
+		existing, err := client.RedirectRules(ctx)
+		if err != nil {
+			return err
+		}
+
+		var rules []cloudflare.RedirectRule
+		var failed int
+		for _, pr := range pageRules {
+			if pr.Forward == nil || (pr.Status != "active" && !redirectMigrateAll) {
+				continue
+			}
+			if migrated(existing, pr.ID) {
+				fmt.Printf("  skip  %s (already migrated)\n", pr.Pattern)
+				continue
+			}
+			spec, err := redirect.FromPageRule(pr.Pattern, pr.Forward.URL, pr.Forward.StatusCode)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "  fail  %s: %v\n", pr.Pattern, err)
+				failed++
+				continue
+			}
+			rule, err := redirectRule(spec, fmt.Sprintf("%s (page rule %s)", spec, pr.ID))
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "  fail  %s: %v\n", pr.Pattern, err)
+				failed++
+				continue
+			}
+			fmt.Printf("  add   %s\n", spec)
+			rules = append(rules, rule)
+		}
+
+		switch {
+		case len(rules) == 0 && failed == 0:
+			fmt.Println("No forwarding page rules to migrate")
+			return nil
+		case len(rules) > 0 && redirectDryRun:
+			fmt.Printf("Would add %d redirect rule(s)\n", len(rules))
+		case len(rules) > 0:
+			if err := client.AddRedirectRules(ctx, rules...); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Added %d redirect rule(s); disable the page rules once they work as expected\n", len(rules))
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d page rule(s) could not be converted; recreate them by hand with 'cfcli rules redirect add'", failed)
+		}
+		return nil
+	},
+}
+
+var rulesPageRulesCmd = &cobra.Command{
+	Use:   "page-rules",
+	Short: "List the zone's legacy page rules",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		rules, err := client.PageRules(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if strings.ToLower(format) == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(rules)
+		}
+		if len(rules) == 0 {
+			fmt.Println("No page rules found")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Priority", "Pattern", "Actions", "Status", "ID")
+		for _, r := range rules {
+			if err := table.Append(strconv.Itoa(r.Priority), r.Pattern, describePageRuleActions(r), r.Status, r.ID); err != nil {
+				return err
+			}
+		}
+		return table.Render()
+	},
+}
+
+// redirectRule builds the rule for spec.
+func redirectRule(spec redirect.Spec, description string) (cloudflare.RedirectRule, error) {
+	expr, err := spec.Expression()
+	if err != nil {
+		return cloudflare.RedirectRule{}, err
+	}
+	value, targetExpr, err := spec.Target()
+	if err != nil {
+		return cloudflare.RedirectRule{}, err
+	}
+	if description == "" {
+		description = spec.String()
+	}
+	return cloudflare.RedirectRule{
+		Description:      description,
+		Expression:       expr,
+		Enabled:          true,
+		TargetURL:        value,
+		TargetExpression: targetExpr,
+		StatusCode:       spec.Status,
+		PreserveQuery:    spec.PreserveQuery,
+	}, nil
+}
+
+func printRedirectRule(verb string, r cloudflare.RedirectRule) {
+	fmt.Printf("%s redirect rule: %s\n", verb, r.Description)
+	fmt.Printf("  Expression: %s\n", r.Expression)
+	if r.TargetURL != "" {
+		fmt.Printf("  Target:     %s (%d)\n", r.TargetURL, r.StatusCode)
+	} else {
+		fmt.Printf("  Target:     %s (%d)\n", r.TargetExpression, r.StatusCode)
+	}
+}
+
+// migrated reports whether a redirect rule was migrated from the page
+// rule with the given ID.
+func migrated(rules []cloudflare.RedirectRule, pageRuleID string) bool {
+	for _, r := range rules {
+		if strings.Contains(r.Description, "(page rule "+pageRuleID+")") {
+			return true
+		}
+	}
+	return false
+}
+
+func describePageRuleActions(r cloudflare.PageRule) string {
+	if r.Forward != nil {
+		return fmt.Sprintf("forward → %s (%d)", r.Forward.URL, r.Forward.StatusCode)
+	}
+	var parts []string
+	for id, v := range r.Actions {
+		parts = append(parts, fmt.Sprintf("%s=%v", id, v))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	rulesRedirectAddCmd.Flags().BoolVar(&redirectPreserveQuery, "preserve-query", false, "Keep the request's query string")
+	rulesRedirectAddCmd.Flags().StringVar(&redirectDescription, "description", "", `Rule description (default: the "from → to status" spec)`)
+	rulesRedirectAddCmd.Flags().BoolVar(&redirectDryRun, "dry-run", false, "Show the generated rule without adding it")
+	rulesRedirectMigrateCmd.Flags().BoolVar(&redirectDryRun, "dry-run", false, "Show the conversions without adding rules")
+	rulesRedirectMigrateCmd.Flags().BoolVar(&redirectMigrateAll, "all", false, "Also convert disabled page rules")
+
+	rulesRedirectCmd.AddCommand(rulesRedirectListCmd, rulesRedirectAddCmd, rulesRedirectRemoveCmd, rulesRedirectMigrateCmd)
+	rulesCmd.AddCommand(rulesRedirectCmd, rulesPageRulesCmd)
+	rootCmd.AddCommand(rulesCmd)
+}
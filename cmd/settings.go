@@ -0,0 +1,352 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/zonesettings"
+	"github.com/spf13/cobra"
+)
+
+var (
+	settingsAgainst string
+	settingsFile    string
+	settingsDryRun  bool
+)
+
+var settingsCmd = &cobra.Command{
+	Use:   "settings",
+	Short: "Read and change zone settings",
+	Long: `Read and change zone settings such as the SSL mode, minimum TLS version,
+HSTS and security level. Setting names may use dashes or underscores, and
+"hsts" is accepted for security_header.`,
+}
+
+var settingsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings of the zone",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		settings, err := client.ZoneSettings(context.Background())
+		if err != nil {
+			return err
+		}
+		sort.Slice(settings, func(i, j int) bool { return settings[i].ID < settings[j].ID })
+
+		switch strings.ToLower(format) {
+		case "json":
+			values := make(map[string]any, len(settings))
+			for _, s := range settings {
+				values[s.ID] = s.Value
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(values)
+		case "csv":
+			writer := csv.NewWriter(os.Stdout)
+			defer writer.Flush()
+			if err := writer.Write([]string{"Setting", "Value", "Editable"}); err != nil {
+				return err
+			}
+			for _, s := range settings {
+				if err := writer.Write([]string{s.ID, zonesettings.Format(s.ID, s.Value), fmt.Sprint(s.Editable)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Setting", "Value", "Editable")
+		for _, s := range settings {
+			editable := " "
+			if s.Editable {
+				editable = "✓"
+			}
+			if err := table.Append(s.ID, zonesettings.Format(s.ID, s.Value), editable); err != nil {
+				return err
+			}
+		}
+		return table.Render()
+	},
+}
+
+var settingsGetCmd = &cobra.Command{
+	Use:   "get <setting>...",
+	Short: "Show the value of one or more settings",
+	Long: `Show the value of one or more settings. With a single setting only the
+value is printed, for use in scripts.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		values, err := client.ZoneSettingValues(context.Background())
+		if err != nil {
+			return err
+		}
+
+		result := make(map[string]any, len(args))
+		ids := make([]string, len(args))
+		for i, name := range args {
+			id := zonesettings.CanonicalID(name)
+			v, ok := values[id]
+			if !ok {
+				return fmt.Errorf("zone %s has no setting %q", client.ZoneName(), name)
+			}
+			ids[i] = id
+			result[id] = v
+		}
+
+		if strings.ToLower(format) == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(result)
+		}
+		if len(ids) == 1 {
+			fmt.Println(zonesettings.Format(ids[0], result[ids[0]]))
+			return nil
+		}
+		for _, id := range ids {
+			fmt.Printf("%s: %s\n", id, zonesettings.Format(id, result[id]))
+		}
+		return nil
+	},
+}
+
+var settingsSetCmd = &cobra.Command{
+	Use:   "set <setting> <value>",
+	Short: "Change a setting",
+	Long: `Change a setting. Values are checked before anything is sent: on/off
+settings take on or off, others one of their listed values or a number in
+range. HSTS takes field=value pairs; fields left out keep their value.
+
+Examples:
+  cfcli -d example.com settings set ssl strict
+  cfcli -d example.com settings set always-use-https on
+  cfcli -d example.com settings set min_tls_version 1.2
+  cfcli -d example.com settings set hsts enabled=true,max_age=31536000,include_subdomains=true`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setting, ok := zonesettings.Lookup(args[0])
+		if !ok {
+			return fmt.Errorf("unknown setting %q (known settings: %s)", args[0], strings.Join(zonesettings.Names(), ", "))
+		}
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		values, err := client.ZoneSettingValues(ctx)
+		if err != nil {
+			return err
+		}
+
+		value, err := setting.Parse(args[1], values[setting.ID])
+		if err != nil {
+			return err
+		}
+		old, new := zonesettings.Format(setting.ID, values[setting.ID]), zonesettings.Format(setting.ID, value)
+		if old == new {
+			fmt.Printf("%s is already %s\n", setting.ID, new)
+			return nil
+		}
+		if settingsDryRun {
+			fmt.Printf("Would set %s: %s → %s\n", setting.ID, old, new)
+			return nil
+		}
+		if _, err := client.UpdateZoneSetting(ctx, setting.ID, value); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Set %s: %s → %s\n", setting.ID, old, new)
+		return nil
+	},
+}
+
+var settingsDiffCmd = &cobra.Command{
+	Use:   "diff --against <zone>",
+	Short: "Compare the zone's settings with another zone's",
+	Long: `Compare every setting of the zone given with -d to the same setting of
+another zone in the account, and list the ones that differ.
+
+Examples:
+  cfcli -d example.com settings diff --against example.org`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if settingsAgainst == "" {
+			return fmt.Errorf("zone to compare with is required (use --against)")
+		}
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		zone := client.ZoneName()
+		values, err := client.ZoneSettingValues(ctx)
+		if err != nil {
+			return err
+		}
+		if err := client.SetZone(ctx, settingsAgainst); err != nil {
+			return err
+		}
+		other := client.ZoneName()
+		otherValues, err := client.ZoneSettingValues(ctx)
+		if err != nil {
+			return err
+		}
+
+		diffs := zonesettings.Diff(values, otherValues)
+		if strings.ToLower(format) == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(diffs)
+		}
+		if len(diffs) == 0 {
+			fmt.Printf("✓ %s and %s have the same settings\n", zone, other)
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Setting", zone, other)
+		for _, d := range diffs {
+			if err := table.Append(d.ID, d.Value, d.OtherValue); err != nil {
+				return err
+			}
+		}
+		return table.Render()
+	},
+}
+
+var settingsApplyCmd = &cobra.Command{
+	Use:   "apply -f <file>",
+	Short: "Bring the zone's settings in line with a baseline file",
+	Long: `Apply a baseline of settings from a YAML file ("-" for stdin). The file
+maps setting names to values; HSTS takes a map of its fields. Only settings
+whose value differs are changed, and the whole file is validated before
+anything is sent.
+
+Example baseline:
+
+  ssl: strict
+  always_use_https: on
+  min_tls_version: 1.2
+  tls_1_3: zrt
+  security_level: medium
+  hsts:
+    enabled: true
+    max_age: 31536000
+    include_subdomains: true
+
+Examples:
+  cfcli -d example.com settings apply -f baseline.yaml --dry-run
+  cfcli -d example.com settings apply baseline.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, data, err := readInputFile(cmd, args, settingsFile)
+		if err != nil {
+			return err
+		}
+		baseline, err := zonesettings.ParseBaseline(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+
+		client, err := domainClient()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		values, err := client.ZoneSettingValues(ctx)
+		if err != nil {
+			return err
+		}
+		changes, err := zonesettings.Plan(values, baseline)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			fmt.Printf("✓ %s already matches %s\n", client.ZoneName(), path)
+			return nil
+		}
+
+		for i, c := range changes {
+			if settingsDryRun {
+				fmt.Printf("Would set %s: %s → %s\n", c.ID, c.Old, c.New)
+				continue
+			}
+			if _, err := client.UpdateZoneSetting(ctx, c.ID, c.Value); err != nil {
+				return fmt.Errorf("%w (%d of %d settings changed)", err, i, len(changes))
+			}
+			fmt.Printf("✓ Set %s: %s → %s\n", c.ID, c.Old, c.New)
+		}
+		return nil
+	},
+}
+
+// readInputFile reads the file named by the argument, --file or -f, or
+// stdin for "-". Commands that read a file have no use for the global
+// --format flag, so -f names the file there, as their usage lines suggest.
+func readInputFile(cmd *cobra.Command, args []string, file string) (string, []byte, error) {
+	path := file
+	switch {
+	case len(args) > 0:
+		path = args[0]
+	case path == "" && cmd.Flags().Changed("format"):
+		path = format
+	}
+	if path == "" {
+		return "", nil, fmt.Errorf("file is required (use -f or --file)")
+	}
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	return path, data, err
+}
+
+// knownSettingsHelp lists the settings 'settings set' and 'settings apply'
+// can change, with their values.
+func knownSettingsHelp() string {
+	var b strings.Builder
+	b.WriteString("\n\nSettings:\n")
+	for _, s := range zonesettings.All() {
+		values := "on, off"
+		switch s.Kind {
+		case zonesettings.Enum:
+			values = strings.Join(s.Values, ", ")
+		case zonesettings.Int:
+			values = fmt.Sprintf("%d-%d", s.Min, s.Max)
+		case zonesettings.HSTS:
+			values = "enabled, max_age, include_subdomains, preload, nosniff"
+		}
+		fmt.Fprintf(&b, "  %-26s %s (%s)\n", s.ID, s.Description, values)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func init() {
+	settingsSetCmd.Long += knownSettingsHelp()
+
+	settingsDiffCmd.Flags().StringVar(&settingsAgainst, "against", "", "Zone to compare with")
+	settingsApplyCmd.Flags().StringVar(&settingsFile, "file", "", `Baseline file ("-" for stdin)`)
+	settingsSetCmd.Flags().BoolVar(&settingsDryRun, "dry-run", false, "Show the change without making it")
+	settingsApplyCmd.Flags().BoolVar(&settingsDryRun, "dry-run", false, "Show the changes without making them")
+
+	settingsCmd.AddCommand(settingsListCmd, settingsGetCmd, settingsSetCmd, settingsDiffCmd, settingsApplyCmd)
+	rootCmd.AddCommand(settingsCmd)
+}
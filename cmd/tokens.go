@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tokenName    string
+	tokenZones   []string
+	tokenPerms   []string
+	tokenTTL     time.Duration
+	tokenIPAllow []string
+	tokenSaveAs  string
+)
+
+var tokensCmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "Manage scoped API tokens",
+	Long: `List, create, roll and revoke API tokens. The token used to run these
+commands needs the "API Tokens Write" permission.`,
+}
+
+var tokensListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List API tokens",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTokenClient()
+		if err != nil {
+			return err
+		}
+
+		tokens, err := client.ListTokens(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if strings.ToLower(format) == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(tokens)
+		}
+
+		if len(tokens) == 0 {
+			fmt.Println("No tokens found")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Name", "Status", "Expires", "ID")
+		for _, t := range tokens {
+			expires := "never"
+			if t.ExpiresOn != nil {
+				expires = t.ExpiresOn.Format(time.RFC3339)
+			}
+			if err := table.Append(t.Name, t.Status, expires, t.ID); err != nil {
+				return err
+			}
+		}
+		return table.Render()
+	},
+}
+
+var tokensCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a zone-scoped API token",
+	Long: `Create an API token limited to specific zones and permissions. The token
+value is printed once; use --save-as to store it as a named account.
+
+Permissions accept short names (` + strings.Join(cloudflare.PermissionAliases(), ", ") + `)
+or full permission group names such as "DNS Write".
+
+Examples:
+  cfcli tokens create --zone example.com --perm dns:edit --ttl 24h
+  cfcli tokens create --zone example.com --perm dns:edit --perm zone:read \
+      --ip-allow 10.0.0.0/8 --name ci-deploy --save-as ci`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(tokenZones) == 0 {
+			return fmt.Errorf("at least one zone is required (use --zone)")
+		}
+		if len(tokenPerms) == 0 {
+			return fmt.Errorf("at least one permission is required (use --perm)")
+		}
+		for _, ip := range tokenIPAllow {
+			if net.ParseIP(ip) == nil {
+				if _, _, err := net.ParseCIDR(ip); err != nil {
+					return fmt.Errorf("invalid --ip-allow value %q: expected an IP address or CIDR", ip)
+				}
+			}
+		}
+
+		name := tokenName
+		if name == "" {
+			name = fmt.Sprintf("cfcli %s %s", strings.Join(tokenZones, ","), time.Now().UTC().Format("2006-01-02T15:04Z"))
+		}
+
+		client, err := newTokenClient()
+		if err != nil {
+			return err
+		}
+
+		token, err := client.CreateToken(context.Background(), cloudflare.TokenRequest{
+			Name:        name,
+			Zones:       tokenZones,
+			Permissions: tokenPerms,
+			TTL:         tokenTTL,
+			IPAllow:     tokenIPAllow,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Created token %s (ID: %s)\n", token.Name, token.ID)
+		if token.ExpiresOn != nil {
+			fmt.Printf("  Expires: %s\n", token.ExpiresOn.Format(time.RFC3339))
+		}
+		fmt.Println()
+		fmt.Println("Token value (shown only once):")
+		fmt.Println(token.Value)
+
+		if tokenSaveAs != "" {
+			return saveTokenAsAccount(tokenSaveAs, token.Value, tokenZones[0])
+		}
+		return nil
+	},
+}
+
+var tokensRollCmd = &cobra.Command{
+	Use:   "roll <token-id>",
+	Short: "Generate a new secret for an API token",
+	Long: `Generate a new secret for an API token. The old value stops working
+immediately. Use --save-as to update a named account with the new value.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTokenClient()
+		if err != nil {
+			return err
+		}
+
+		value, err := client.RollToken(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Rolled token %s\n\n", args[0])
+		fmt.Println("New token value (shown only once):")
+		fmt.Println(value)
+
+		if tokenSaveAs != "" {
+			return saveTokenAsAccount(tokenSaveAs, value, "")
+		}
+		return nil
+	},
+}
+
+var tokensRevokeCmd = &cobra.Command{
+	Use:     "revoke <token-id>",
+	Aliases: []string{"rm", "delete"},
+	Short:   "Revoke (delete) an API token",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newTokenClient()
+		if err != nil {
+			return err
+		}
+
+		if err := client.DeleteToken(context.Background(), args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Revoked token %s\n", args[0])
+		return nil
+	},
+}
+
+func newTokenClient() (*cloudflare.Client, error) {
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+	}
+	if cfg.Email != "" {
+		return nil, fmt.Errorf("token management requires an API token, not a global API key")
+	}
+	return newClient()
+}
+
+// saveTokenAsAccount stores value as the token of the named account,
+// creating the account if needed. domain is only set on new accounts.
+func saveTokenAsAccount(name, value, domain string) error {
+	path, cf, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	if cf.Accounts == nil {
+		cf.Accounts = make(map[string]config.AccountConfig)
+	}
+	acc, exists := cf.Accounts[name]
+	acc.Token = value
+	acc.Email = ""
+	if !exists {
+		acc.Domain = domain
+	}
+	cf.Accounts[name] = acc
+
+	if err := config.WriteConfigFile(path, cf); err != nil {
+		return err
+	}
+	fmt.Printf("\n✓ Saved token as account %s in %s\n", name, path)
+	return nil
+}
+
+func init() {
+	tokensCreateCmd.Flags().StringVar(&tokenName, "name", "", "Token name (default derived from zones and time)")
+	tokensCreateCmd.Flags().StringSliceVar(&tokenZones, "zone", nil, "Zone the token may access (repeatable)")
+	tokensCreateCmd.Flags().StringSliceVar(&tokenPerms, "perm", nil, "Permission to grant, e.g. dns:edit (repeatable)")
+	tokensCreateCmd.Flags().DurationVar(&tokenTTL, "ttl", 0, "Token lifetime, e.g. 24h (default never expires)")
+	tokensCreateCmd.Flags().StringSliceVar(&tokenIPAllow, "ip-allow", nil, "Restrict use to this IP or CIDR (repeatable)")
+	tokensCreateCmd.Flags().StringVar(&tokenSaveAs, "save-as", "", "Save the token as this named account in the config file")
+	tokensRollCmd.Flags().StringVar(&tokenSaveAs, "save-as", "", "Update this named account with the new token value")
+
+	tokensCmd.AddCommand(tokensListCmd)
+	tokensCmd.AddCommand(tokensCreateCmd)
+	tokensCmd.AddCommand(tokensRollCmd)
+	tokensCmd.AddCommand(tokensRevokeCmd)
+	rootCmd.AddCommand(tokensCmd)
+}
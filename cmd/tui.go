@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/config"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and edit DNS records interactively",
+	Long: `Open an interactive table of the zone's DNS records. Without a domain the
+zone picker is shown first.
+
+Keys:
+  /         filter (terms match type, name or content; type:mx name:www)
+  s / S     sort by the next column / reverse the order
+  enter, e  edit the selected record
+  p         toggle proxying
+  d         delete the selected record (asks for confirmation)
+  r         reload the records
+  z         switch zone
+  a         switch account (when several are configured)
+  q         quit
+
+Examples:
+  cfcli -d example.com tui
+  cfcli -u work tui`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Token == "" {
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+		}
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		opts := tui.Options{
+			Client:  client,
+			Zone:    cfg.Domain,
+			Account: cfg.Account,
+		}
+		if _, cf, err := readConfigFile(); err == nil {
+			opts.Accounts = cf.AccountNames()
+		}
+		opts.Connect = func(account string) (*cloudflare.Client, string, error) {
+			c, err := config.LoadConfig(cfgFile, account, nil)
+			if err != nil {
+				return nil, "", err
+			}
+			if c.Token == "" {
+				return nil, "", fmt.Errorf("account %q has no token", account)
+			}
+			client, err := clientFor(c)
+			if err != nil {
+				return nil, "", err
+			}
+			return client, c.Domain, nil
+		}
+		return tui.Run(opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
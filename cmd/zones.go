@@ -2,43 +2,105 @@ package cmd
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/dnsquery"
 	"github.com/spf13/cobra"
 )
 
+var (
+	zoneJumpStart     bool
+	zoneType          string
+	zoneAccountID     string
+	zoneDeleteConfirm string
+	zoneStatusCheck   bool
+	zoneResolver      string
+
+	zoneStatusFilter  string
+	zoneAccountFilter string
+	zoneCountRecords  bool
+)
+
 var zonesCmd = &cobra.Command{
 	Use:   "zones",
-	Short: "List all zones in your Cloudflare account",
+	Short: "List and manage the zones in your Cloudflare account",
+	Long: `List the zones the API token can see, with their status, plan, type,
+nameservers and creation date. Use 'zones show' for everything about one
+zone.
+
+Record counts take an extra API request per zone, so they are only shown
+with --count-records.
+
+Examples:
+  cfcli zones
+  cfcli zones --status pending
+  cfcli zones --account-id Work --count-records
+  cfcli zones -f json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if cfg.Token == "" {
-			return fmt.Errorf("API token is required (use -k or set CF_API_KEY)")
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
 		}
 
-		client, err := cloudflare.NewClient(cfg.Token, cfg.Email)
+		client, err := newClient()
 		if err != nil {
 			return err
 		}
 		ctx := context.Background()
 
-		zones, err := client.ListZones(ctx)
+		// Status and nameservers change, so never show them from the cache.
+		zones, err := client.RefreshZones(ctx)
 		if err != nil {
 			return err
 		}
+		zones = cloudflare.FilterZones(zones, zoneStatusFilter, zoneAccountFilter)
+
+		summaries := make([]cloudflare.ZoneInfo, len(zones))
+		for i := range zones {
+			summaries[i] = cloudflare.NewZoneInfo(&zones[i])
+			if zoneCountRecords {
+				n, err := client.CountDNSRecords(ctx, zones[i].ID)
+				if err != nil {
+					return err
+				}
+				summaries[i].Records = &n
+			}
+		}
 
-		if len(zones) == 0 {
+		switch strings.ToLower(format) {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(summaries)
+		case "csv":
+			return outputZonesCSV(summaries)
+		}
+
+		if len(summaries) == 0 {
 			fmt.Println("No zones found")
 			return nil
 		}
 
 		table := tablewriter.NewWriter(os.Stdout)
-		table.Header("Name", "Status", "ID")
+		header := []string{"Name", "Status", "Plan", "Type", "Nameservers", "Created"}
+		if zoneCountRecords {
+			header = append(header, "Records")
+		}
+		table.Header(append(header, "ID"))
 
-		for _, zone := range zones {
-			if err := table.Append(zone.Name, string(zone.Status), zone.ID); err != nil {
+		for _, z := range summaries {
+			row := []string{z.Name, zoneStatusText(z), orDash(z.Plan), z.Type, orDash(strings.Join(z.NameServers, ", ")), formatDate(z.CreatedOn)}
+			if z.Records != nil {
+				row = append(row, strconv.Itoa(*z.Records))
+			}
+			if err := table.Append(append(row, z.ID)); err != nil {
 				return err
 			}
 		}
@@ -47,6 +109,350 @@ var zonesCmd = &cobra.Command{
 	},
 }
 
+var zonesShowCmd = &cobra.Command{
+	Use:   "show [domain]",
+	Short: "Show everything about a zone",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, _, err := zoneClient(args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		zone, err := client.Zone(ctx)
+		if err != nil {
+			return err
+		}
+		z := cloudflare.NewZoneInfo(zone)
+		if n, err := client.CountDNSRecords(ctx, zone.ID); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not count DNS records: %v\n", err)
+		} else {
+			z.Records = &n
+		}
+
+		if strings.ToLower(format) == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(z)
+		}
+
+		records := "-"
+		if z.Records != nil {
+			records = strconv.Itoa(*z.Records)
+		}
+		fmt.Printf("Name:                  %s\n", z.Name)
+		fmt.Printf("ID:                    %s\n", z.ID)
+		fmt.Printf("Status:                %s\n", zoneStatusText(z))
+		fmt.Printf("Type:                  %s\n", z.Type)
+		fmt.Printf("Plan:                  %s\n", orDash(z.Plan))
+		fmt.Printf("Account:               %s\n", orDash(strings.TrimSpace(z.Account+" "+parenthesize(z.AccountID))))
+		fmt.Printf("Nameservers:           %s\n", orDash(strings.Join(z.NameServers, ", ")))
+		fmt.Printf("Original nameservers:  %s\n", orDash(strings.Join(z.OriginalNameServers, ", ")))
+		fmt.Printf("Original registrar:    %s\n", orDash(z.OriginalRegistrar))
+		fmt.Printf("Original DNS host:     %s\n", orDash(z.OriginalDNSHost))
+		if z.VerificationKey != "" {
+			fmt.Printf("Verification key:      %s\n", z.VerificationKey)
+		}
+		fmt.Printf("DNS records:           %s\n", records)
+		fmt.Printf("Created:               %s\n", formatTime(z.CreatedOn))
+		fmt.Printf("Modified:              %s\n", formatTime(z.ModifiedOn))
+		return nil
+	},
+}
+
+func zoneStatusText(z cloudflare.ZoneInfo) string {
+	if z.Paused {
+		return z.Status + " (paused)"
+	}
+	return z.Status
+}
+
+func outputZonesCSV(zones []cloudflare.ZoneInfo) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	header := []string{"ID", "Name", "Status", "Paused", "Type", "Plan", "Account", "Nameservers",
+		"Original Nameservers", "Original Registrar", "Records", "Created", "Modified"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, z := range zones {
+		records := ""
+		if z.Records != nil {
+			records = strconv.Itoa(*z.Records)
+		}
+		row := []string{
+			z.ID,
+			z.Name,
+			z.Status,
+			strconv.FormatBool(z.Paused),
+			z.Type,
+			z.Plan,
+			z.Account,
+			strings.Join(z.NameServers, " "),
+			strings.Join(z.OriginalNameServers, " "),
+			z.OriginalRegistrar,
+			records,
+			z.CreatedOn.Format(time.RFC3339),
+			z.ModifiedOn.Format(time.RFC3339),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+func parenthesize(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "(" + s + ")"
+}
+
+var zonesCreateCmd = &cobra.Command{
+	Use:   "create <domain>",
+	Short: "Add a domain to Cloudflare",
+	Long: `Add a domain as a new zone and print the nameservers to set at the
+registrar. Full zones are activated once the registrar delegates to them;
+partial (CNAME setup) zones are verified with a TXT record instead.
+
+Examples:
+  cfcli zones create example.com
+  cfcli zones create example.com --jump-start
+  cfcli zones create example.com --type partial --account-id <id>`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cfg.Token == "" {
+			return fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+		}
+		if zoneType != "full" && zoneType != "partial" {
+			return fmt.Errorf("invalid zone type %q (use full or partial)", zoneType)
+		}
+
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+		zone, err := client.CreateZone(context.Background(), args[0], zoneAccountID, zoneJumpStart, zoneType)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Created zone %s (ID: %s, status: %s)\n", zone.Name, zone.ID, zone.Status)
+		if zoneType == "partial" {
+			if zone.VerificationKey != "" {
+				fmt.Printf("\nAdd this TXT record at your current DNS provider to verify ownership:\n  cloudflare-verify.%s  TXT  %s\n", zone.Name, zone.VerificationKey)
+			}
+			return nil
+		}
+		fmt.Println("\nSet these nameservers at your registrar:")
+		for _, ns := range zone.NameServers {
+			fmt.Printf("  %s\n", ns)
+		}
+		fmt.Printf("\nThen run 'cfcli zones status %s --check' to request activation.\n", zone.Name)
+		return nil
+	},
+}
+
+var zonesDeleteCmd = &cobra.Command{
+	Use:   "delete <domain>",
+	Short: "Delete a zone and all of its records",
+	Long: `Delete a zone and all of its DNS records and settings. This cannot be
+undone. You are asked to type the zone name to confirm; pass it with
+--confirm for scripts.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, zone, err := zoneClient(args)
+		if err != nil {
+			return err
+		}
+
+		typed := zoneDeleteConfirm
+		if typed == "" {
+			fmt.Printf("This permanently deletes %s and all of its DNS records.\n", zone)
+			if typed, err = prompt(cmd, "Type the zone name to confirm: "); err != nil {
+				return err
+			}
+		}
+		if !strings.EqualFold(strings.TrimSuffix(typed, "."), zone) {
+			return fmt.Errorf("confirmation %q does not match %s; nothing deleted", typed, zone)
+		}
+
+		if err := client.DeleteZone(context.Background()); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Deleted zone %s\n", zone)
+		return nil
+	},
+}
+
+var zonesPauseCmd = &cobra.Command{
+	Use:   "pause [domain]",
+	Short: "Pause Cloudflare on a zone (DNS only)",
+	Long: `Pause Cloudflare on a zone. Proxied records then resolve straight to the
+origin and caching, WAF and other features stop; DNS keeps working.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setZonePaused(args, true)
+	},
+}
+
+var zonesUnpauseCmd = &cobra.Command{
+	Use:   "unpause [domain]",
+	Short: "Resume Cloudflare on a paused zone",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setZonePaused(args, false)
+	},
+}
+
+var zonesStatusCmd = &cobra.Command{
+	Use:   "status [domain]",
+	Short: "Check a zone's activation and nameserver delegation",
+	Long: `Compare the nameservers the domain is delegated to with the ones
+Cloudflare assigned, and show the zone's status. With --check, ask Cloudflare
+to re-check a pending zone now instead of waiting for its periodic check.
+
+Delegation is looked up through --resolver, which may cache the old
+nameservers for up to the NS record's TTL (often 48 hours).
+
+Examples:
+  cfcli zones status example.com
+  cfcli zones status example.com --check`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, name, err := zoneClient(args)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		zone, err := client.Zone(ctx)
+		if err != nil {
+			return err
+		}
+
+		var current []string
+		answer, err := dnsquery.Resolver{Server: zoneResolver}.Query(ctx, name, "NS")
+		switch {
+		case err != nil:
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not look up the current nameservers: %v\n", err)
+		case answer.Rcode != "NOERROR":
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: NS lookup for %s returned %s\n", name, answer.Rcode)
+		default:
+			current = answer.Values
+		}
+		delegated := cloudflare.SameNameservers(zone.NameServers, current)
+
+		status := zone.Status
+		if zone.Paused {
+			status += " (paused)"
+		}
+		fmt.Printf("Zone:        %s\n", zone.Name)
+		fmt.Printf("Status:      %s\n", status)
+		fmt.Printf("Type:        %s\n", zone.Type)
+		fmt.Printf("Assigned NS: %s\n", strings.Join(zone.NameServers, ", "))
+		fmt.Printf("Current NS:  %s\n", orDash(strings.Join(current, ", ")))
+		switch {
+		case zone.Type == "partial":
+			fmt.Println("Delegation:  not required for partial zones")
+		case delegated:
+			fmt.Println("Delegation:  ✓ matches Cloudflare's nameservers")
+		default:
+			fmt.Println("Delegation:  ✗ does not match yet; update the nameservers at your registrar")
+		}
+
+		if !zoneStatusCheck {
+			return nil
+		}
+		if zone.Status == "active" {
+			fmt.Println("Zone is already active; no activation check needed")
+			return nil
+		}
+		if zone.Type != "partial" && !delegated {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Warning: requesting activation check although the delegation does not match yet")
+		}
+		if err := client.ActivationCheck(ctx); err != nil {
+			return err
+		}
+		fmt.Println("✓ Activation check requested; the status updates within a few minutes")
+		return nil
+	},
+}
+
+// zoneClient returns a client with the zone named by args[0], or the
+// configured domain, selected.
+func zoneClient(args []string) (*cloudflare.Client, string, error) {
+	if cfg.Token == "" {
+		return nil, "", fmt.Errorf("API token is required (use -k or set CF_API_TOKEN)")
+	}
+	name := cfg.Domain
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if name == "" {
+		return nil, "", fmt.Errorf("domain is required (give it as an argument, use -d or set CF_API_DOMAIN)")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := client.SetZone(context.Background(), name); err != nil {
+		return nil, "", err
+	}
+	return client, client.ZoneName(), nil
+}
+
+func setZonePaused(args []string, paused bool) error {
+	client, name, err := zoneClient(args)
+	if err != nil {
+		return err
+	}
+	if _, err := client.SetPaused(context.Background(), paused); err != nil {
+		return err
+	}
+	if paused {
+		fmt.Printf("✓ Paused %s; records are DNS only until unpaused\n", name)
+	} else {
+		fmt.Printf("✓ Unpaused %s\n", name)
+	}
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func init() {
+	zonesCreateCmd.Flags().BoolVar(&zoneJumpStart, "jump-start", false, "Import the domain's existing DNS records")
+	zonesCreateCmd.Flags().StringVarP(&zoneType, "type", "t", "full", "Zone type: full or partial (CNAME setup)")
+	zonesCreateCmd.Flags().StringVar(&zoneAccountID, "account-id", "", "Cloudflare account ID (default: the token's only account)")
+	zonesDeleteCmd.Flags().StringVar(&zoneDeleteConfirm, "confirm", "", "Zone name, to confirm deletion without a prompt")
+	zonesStatusCmd.Flags().BoolVar(&zoneStatusCheck, "check", false, "Request an activation check for a pending zone")
+	zonesStatusCmd.Flags().StringVar(&zoneResolver, "resolver", "1.1.1.1", "Resolver used to look up the current delegation")
+
+	zonesCmd.Flags().StringVar(&zoneStatusFilter, "status", "", "Only list zones with this status (active, pending, moved, ...)")
+	zonesCmd.Flags().StringVar(&zoneAccountFilter, "account-id", "", "Only list zones in this Cloudflare account (ID or name)")
+	zonesCmd.Flags().BoolVar(&zoneCountRecords, "count-records", false, "Show the number of DNS records per zone (one extra request per zone)")
+
+	zonesCmd.AddCommand(zonesShowCmd, zonesCreateCmd, zonesDeleteCmd, zonesPauseCmd, zonesUnpauseCmd, zonesStatusCmd)
 	rootCmd.AddCommand(zonesCmd)
 }
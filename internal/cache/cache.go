@@ -0,0 +1,115 @@
+// Package cache stores small API results on disk so that repeated
+// invocations can skip lookups that rarely change, such as zone IDs.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultTTL is how long cached entries are used before being refreshed.
+const DefaultTTL = time.Hour
+
+// Cache is a directory of JSON entries that expire after TTL.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+type entry struct {
+	StoredAt time.Time       `json:"stored_at"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// Dir returns the root cache directory, $XDG_CACHE_HOME/cfcli or the
+// platform equivalent.
+func Dir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine cache directory: %w", err)
+	}
+	return filepath.Join(base, "cfcli"), nil
+}
+
+// Key derives a cache namespace from the account name and credentials, so
+// that different tokens never share entries even under the same name.
+func Key(account, token string) string {
+	if account == "" {
+		account = "default"
+	}
+	sum := sha256.Sum256([]byte(token))
+	return account + "-" + hex.EncodeToString(sum[:])[:12]
+}
+
+// New returns the cache for the given key.
+func New(key string, ttl time.Duration) (*Cache, error) {
+	root, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{dir: filepath.Join(root, key), ttl: ttl}, nil
+}
+
+// Get decodes the named entry into v. It returns false when the entry is
+// missing, expired or unreadable.
+func (c *Cache) Get(name string, v interface{}) bool {
+	data, err := os.ReadFile(c.path(name))
+	if err != nil {
+		return false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false
+	}
+	if time.Since(e.StoredAt) > c.ttl {
+		return false
+	}
+	return json.Unmarshal(e.Data, v) == nil
+}
+
+// Put stores v under name.
+func (c *Cache) Put(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	out, err := json.Marshal(entry{StoredAt: time.Now(), Data: data})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path(name), out, 0o600)
+}
+
+// Clear removes every entry in this cache.
+func (c *Cache) Clear() error {
+	return removeDir(c.dir)
+}
+
+// ClearAll removes the caches of all accounts.
+func ClearAll() error {
+	root, err := Dir()
+	if err != nil {
+		return err
+	}
+	return removeDir(root)
+}
+
+func (c *Cache) path(name string) string {
+	return filepath.Join(c.dir, name+".json")
+}
+
+func removeDir(dir string) error {
+	if err := os.RemoveAll(dir); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	c, err := New(Key("work", "token"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]string
+	if c.Get("zones", &got) {
+		t.Fatal("Get() on empty cache returned true")
+	}
+
+	if err := c.Put("zones", map[string]string{"example.com": "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Get("zones", &got) || got["example.com"] != "abc" {
+		t.Fatalf("Get() = %v, want cached value", got)
+	}
+
+	expired := &Cache{dir: c.dir, ttl: -time.Second}
+	if expired.Get("zones", &got) {
+		t.Error("Get() returned an expired entry")
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Get("zones", &got) {
+		t.Error("Get() after Clear() returned true")
+	}
+}
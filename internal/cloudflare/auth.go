@@ -0,0 +1,115 @@
+package cloudflare
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// TokenPolicy is a simplified view of an API token policy.
+type TokenPolicy struct {
+	Effect      string
+	Permissions []string
+	Resources   []string
+}
+
+// TokenInfo describes the API token the client authenticates with.
+type TokenInfo struct {
+	ID        string
+	Status    string
+	NotBefore *time.Time
+	ExpiresOn *time.Time
+
+	// Policies is nil when the token is not allowed to read its own
+	// details; PoliciesErr then holds the reason.
+	Policies    []TokenPolicy
+	PoliciesErr error
+}
+
+// VerifyToken checks the client's API token and, when the token is allowed
+// to read its own details, returns its policies as well.
+func (c *Client) VerifyToken(ctx context.Context) (*TokenInfo, error) {
+	body, err := c.api.VerifyAPIToken(ctx)
+	if err != nil {
+		return nil, apiError(err, "verify API token", "")
+	}
+
+	info := &TokenInfo{
+		ID:     body.ID,
+		Status: body.Status,
+	}
+	if !body.NotBefore.IsZero() {
+		info.NotBefore = &body.NotBefore
+	}
+	if !body.ExpiresOn.IsZero() {
+		info.ExpiresOn = &body.ExpiresOn
+	}
+
+	token, err := c.api.GetAPIToken(ctx, body.ID)
+	if err != nil {
+		info.PoliciesErr = apiError(err, "read token policies", PermAPITokensRead)
+		return info, nil
+	}
+	for _, p := range token.Policies {
+		policy := TokenPolicy{Effect: p.Effect}
+		for _, g := range p.PermissionGroups {
+			policy.Permissions = append(policy.Permissions, g.Name)
+		}
+		for resource := range p.Resources {
+			policy.Resources = append(policy.Resources, resource)
+		}
+		sort.Strings(policy.Resources)
+		info.Policies = append(info.Policies, policy)
+	}
+	return info, nil
+}
+
+// VerifyAPIKey checks legacy global API key credentials and returns the
+// email address of the user they belong to.
+func (c *Client) VerifyAPIKey(ctx context.Context) (string, error) {
+	user, err := c.api.UserDetails(ctx)
+	if err != nil {
+		return "", apiError(err, "verify API key", "")
+	}
+	return user.Email, nil
+}
+
+// DescribeResource turns a token policy resource such as
+// "com.cloudflare.api.account.zone.<id>" into a readable form, using
+// zoneNames to map zone IDs to names.
+func DescribeResource(resource string, zoneNames map[string]string) string {
+	const (
+		zonePrefix    = "com.cloudflare.api.account.zone."
+		accountPrefix = "com.cloudflare.api.account."
+		userPrefix    = "com.cloudflare.api.user."
+	)
+	switch {
+	case resource == zonePrefix+"*":
+		return "all zones"
+	case strings.HasPrefix(resource, zonePrefix):
+		id := strings.TrimPrefix(resource, zonePrefix)
+		if name, ok := zoneNames[id]; ok {
+			return "zone " + name
+		}
+		return "zone " + id
+	case resource == accountPrefix+"*":
+		return "all accounts"
+	case strings.HasPrefix(resource, accountPrefix):
+		return "account " + strings.TrimPrefix(resource, accountPrefix)
+	case strings.HasPrefix(resource, userPrefix):
+		return "user " + strings.TrimPrefix(resource, userPrefix)
+	}
+	return resource
+}
+
+// ZoneNames returns a map from zone ID to zone name.
+func ZoneNames(zones []cloudflare.Zone) map[string]string {
+	names := make(map[string]string, len(zones))
+	for _, z := range zones {
+		names[z.ID] = z.Name
+	}
+	return names
+}
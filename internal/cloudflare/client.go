@@ -6,13 +6,26 @@ import (
 	"strings"
 
 	cloudflare "github.com/cloudflare/cloudflare-go"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cache"
 )
 
 type Client struct {
-	api    *cloudflare.API
-	zoneID string
+	api      *cloudflare.API
+	zoneID   string
+	zoneName string
+
+	// zones caches the result of ListZones for the life of the client.
+	zones []cloudflare.Zone
+
+	// cache, when set, persists zone lookups across invocations.
+	cache *cache.Cache
 }
 
+const (
+	cacheZones   = "zones"
+	cacheZoneIDs = "zone-ids"
+)
+
 type DNSRecord struct {
 	ID       string
 	Type     string
@@ -42,23 +55,114 @@ func NewClient(token, email string) (*Client, error) {
 	return &Client{api: api}, nil
 }
 
+// SetCache enables the on-disk cache for zone lookups.
+func (c *Client) SetCache(cc *cache.Cache) {
+	c.cache = cc
+}
+
+// InvalidateZones drops cached zone lookups, for use after zones are
+// created, deleted or change state.
+func (c *Client) InvalidateZones() {
+	c.zones = nil
+	if c.cache != nil {
+		_ = c.cache.Clear()
+	}
+}
+
 func (c *Client) SetZone(ctx context.Context, domain string) error {
-	// Find zone by name
-	zoneID, err := c.api.ZoneIDByName(domain)
-	if err != nil {
-		return fmt.Errorf("failed to find zone %s: %w", domain, err)
+	name := strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	zoneID, ok := c.cachedZoneID(name)
+	if !ok {
+		// Find zone by name
+		var err error
+		zoneID, err = c.api.ZoneIDByName(domain)
+		if err != nil {
+			return apiError(err, "find zone "+domain, PermZoneRead)
+		}
+		c.storeZoneID(name, zoneID)
 	}
 
 	c.zoneID = zoneID
+	c.zoneName = name
 	return nil
 }
 
+// ForZone returns a new client for the zone named domain, sharing c's API
+// connection and cache but not its zone, so that clients for different
+// zones can be used concurrently.
+func (c *Client) ForZone(ctx context.Context, domain string) (*Client, error) {
+	zc := &Client{api: c.api, cache: c.cache}
+	if err := zc.SetZone(ctx, domain); err != nil {
+		return nil, err
+	}
+	return zc, nil
+}
+
+func (c *Client) cachedZoneID(name string) (string, bool) {
+	if c.cache == nil {
+		return "", false
+	}
+	var ids map[string]string
+	if c.cache.Get(cacheZoneIDs, &ids) && ids[name] != "" {
+		return ids[name], true
+	}
+	var zones []cloudflare.Zone
+	if c.cache.Get(cacheZones, &zones) {
+		for _, z := range zones {
+			if strings.EqualFold(z.Name, name) {
+				return z.ID, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (c *Client) storeZoneID(name, zoneID string) {
+	if c.cache == nil {
+		return
+	}
+	var ids map[string]string
+	if !c.cache.Get(cacheZoneIDs, &ids) {
+		ids = make(map[string]string)
+	}
+	ids[name] = zoneID
+	_ = c.cache.Put(cacheZoneIDs, ids)
+}
+
+// ZoneName returns the name of the zone selected with SetZone or
+// SetZoneForName.
+func (c *Client) ZoneName() string {
+	return c.zoneName
+}
+
 func (c *Client) ListZones(ctx context.Context) ([]cloudflare.Zone, error) {
+	if c.zones != nil {
+		return c.zones, nil
+	}
+
+	var zones []cloudflare.Zone
+	if c.cache != nil && c.cache.Get(cacheZones, &zones) {
+		c.zones = zones
+		return zones, nil
+	}
+
+	return c.RefreshZones(ctx)
+}
+
+// RefreshZones lists the zones from the API, bypassing the cache, and
+// stores the result for later lookups. Use it where the zones' status
+// must be current.
+func (c *Client) RefreshZones(ctx context.Context) ([]cloudflare.Zone, error) {
 	zones, err := c.api.ListZones(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list zones: %w", err)
+		return nil, apiError(err, "list zones", PermZoneRead)
 	}
 
+	c.zones = zones
+	if c.cache != nil {
+		_ = c.cache.Put(cacheZones, zones)
+	}
 	return zones, nil
 }
 
@@ -70,7 +174,7 @@ func (c *Client) ListDNSRecords(ctx context.Context) ([]DNSRecord, error) {
 	rc := cloudflare.ZoneIdentifier(c.zoneID)
 	records, _, err := c.api.ListDNSRecords(ctx, rc, cloudflare.ListDNSRecordsParams{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list DNS records: %w", err)
+		return nil, apiError(err, "list DNS records", PermDNSRead)
 	}
 
 	var dnsRecords []DNSRecord
@@ -109,7 +213,7 @@ func (c *Client) FindDNSRecord(ctx context.Context, name, content string, record
 	rc := cloudflare.ZoneIdentifier(c.zoneID)
 	records, _, err := c.api.ListDNSRecords(ctx, rc, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find DNS records: %w", err)
+		return nil, apiError(err, "find DNS records", PermDNSRead)
 	}
 
 	var dnsRecords []DNSRecord
@@ -155,7 +259,7 @@ func (c *Client) AddDNSRecord(ctx context.Context, recordType, name, content str
 	rc := cloudflare.ZoneIdentifier(c.zoneID)
 	record, err := c.api.CreateDNSRecord(ctx, rc, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create DNS record: %w", err)
+		return nil, apiError(err, "create DNS record", PermDNSWrite)
 	}
 
 	dnsRecord := &DNSRecord{
@@ -198,7 +302,7 @@ func (c *Client) UpdateDNSRecord(ctx context.Context, recordID, recordType, name
 	params.ID = recordID
 	record, err := c.api.UpdateDNSRecord(ctx, rc, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update DNS record: %w", err)
+		return nil, apiError(err, "update DNS record", PermDNSWrite)
 	}
 
 	dnsRecord := &DNSRecord{
@@ -222,7 +326,7 @@ func (c *Client) DeleteDNSRecord(ctx context.Context, recordID string) error {
 	rc := cloudflare.ZoneIdentifier(c.zoneID)
 	err := c.api.DeleteDNSRecord(ctx, rc, recordID)
 	if err != nil {
-		return fmt.Errorf("failed to delete DNS record: %w", err)
+		return apiError(err, "delete DNS record", PermDNSWrite)
 	}
 
 	return nil
@@ -0,0 +1,38 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// DNSSEC returns the DNSSEC state and keys of the current zone.
+func (c *Client) DNSSEC(ctx context.Context) (*cloudflare.ZoneDNSSEC, error) {
+	if c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	}
+	d, err := c.api.ZoneDNSSECSetting(ctx, c.zoneID)
+	if err != nil {
+		return nil, apiError(err, "get DNSSEC status", PermZoneSettingsRead)
+	}
+	return &d, nil
+}
+
+// SetDNSSEC enables or disables DNSSEC signing for the current zone.
+// Enabling leaves the zone pending until the DS record is published at the
+// registrar.
+func (c *Client) SetDNSSEC(ctx context.Context, enabled bool) (*cloudflare.ZoneDNSSEC, error) {
+	if c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	}
+	status := "disabled"
+	if enabled {
+		status = "active"
+	}
+	d, err := c.api.UpdateZoneDNSSEC(ctx, c.zoneID, cloudflare.ZoneDNSSECUpdateOptions{Status: status})
+	if err != nil {
+		return nil, apiError(err, "update DNSSEC", PermZoneSettingsWrite)
+	}
+	return &d, nil
+}
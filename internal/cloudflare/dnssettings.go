@@ -0,0 +1,299 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DNSSettings are a zone's DNS settings. Nil fields are left unchanged by
+// UpdateDNSSettings, so a DNSSettings read from a file only needs the
+// fields it means to set.
+type DNSSettings struct {
+	// FlattenAllCNAMEs flattens every CNAME record instead of only the
+	// one at the zone apex.
+	FlattenAllCNAMEs *bool `json:"flatten_all_cnames,omitempty" yaml:"flatten_all_cnames,omitempty"`
+	// NSTTL is the TTL of the zone's NS records.
+	NSTTL *int `json:"ns_ttl,omitempty" yaml:"ns_ttl,omitempty"`
+	// MultiProvider allows NS records at the apex from other DNS
+	// providers, for zones served by several providers at once.
+	MultiProvider *bool `json:"multi_provider,omitempty" yaml:"multi_provider,omitempty"`
+	// SecondaryOverrides lets proxied records override records
+	// transferred in from a primary nameserver.
+	SecondaryOverrides *bool `json:"secondary_overrides,omitempty" yaml:"secondary_overrides,omitempty"`
+	SOA                *SOA  `json:"soa,omitempty" yaml:"soa,omitempty"`
+
+	// MultiSigner is part of the zone's DNSSEC configuration rather than
+	// its DNS settings: it allows DNSKEYs from other signers.
+	MultiSigner *bool `json:"multi_signer_dnssec,omitempty" yaml:"multi_signer_dnssec,omitempty"`
+}
+
+// SOA holds the zone's custom SOA record fields. Zero fields mean "keep
+// the current value".
+type SOA struct {
+	MName   string `json:"mname,omitempty" yaml:"mname,omitempty"`
+	RName   string `json:"rname,omitempty" yaml:"rname,omitempty"`
+	Refresh int    `json:"refresh,omitempty" yaml:"refresh,omitempty"`
+	Retry   int    `json:"retry,omitempty" yaml:"retry,omitempty"`
+	Expire  int    `json:"expire,omitempty" yaml:"expire,omitempty"`
+	MinTTL  int    `json:"min_ttl,omitempty" yaml:"min_ttl,omitempty"`
+	TTL     int    `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
+
+// DNSSettingNames are the names accepted by ParseDNSSetting.
+var DNSSettingNames = []string{
+	"cname-flattening", "ns-ttl", "multi-provider", "multi-signer", "secondary-overrides",
+	"soa-mname", "soa-rname", "soa-refresh", "soa-retry", "soa-expire", "soa-min-ttl", "soa-ttl",
+}
+
+// ParseDNSSetting returns the settings that set one field, named as in
+// DNSSettingNames, to value. cname-flattening takes "all" or "root";
+// on/off fields take on or off.
+func ParseDNSSetting(name, value string) (DNSSettings, error) {
+	var s DNSSettings
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-")
+	value = strings.TrimSpace(value)
+
+	onOff := func(dst **bool) error {
+		switch strings.ToLower(value) {
+		case "on", "true", "yes":
+			*dst = boolPtr(true)
+		case "off", "false", "no":
+			*dst = boolPtr(false)
+		default:
+			return fmt.Errorf("%s: invalid value %q (use on or off)", name, value)
+		}
+		return nil
+	}
+	number := func() (int, error) {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("%s: invalid number %q", name, value)
+		}
+		return n, nil
+	}
+
+	var err error
+	switch name {
+	case "cname-flattening":
+		switch strings.ToLower(value) {
+		case "all":
+			s.FlattenAllCNAMEs = boolPtr(true)
+		case "root", "apex":
+			s.FlattenAllCNAMEs = boolPtr(false)
+		default:
+			err = fmt.Errorf("%s: invalid value %q (use all or root)", name, value)
+		}
+	case "ns-ttl":
+		var n int
+		if n, err = number(); err == nil {
+			s.NSTTL = &n
+		}
+	case "multi-provider":
+		err = onOff(&s.MultiProvider)
+	case "multi-signer":
+		err = onOff(&s.MultiSigner)
+	case "secondary-overrides":
+		err = onOff(&s.SecondaryOverrides)
+	case "soa-mname":
+		s.SOA = &SOA{MName: value}
+	case "soa-rname":
+		s.SOA = &SOA{RName: value}
+	case "soa-refresh", "soa-retry", "soa-expire", "soa-min-ttl", "soa-ttl":
+		var n int
+		if n, err = number(); err == nil {
+			soa := &SOA{}
+			switch name {
+			case "soa-refresh":
+				soa.Refresh = n
+			case "soa-retry":
+				soa.Retry = n
+			case "soa-expire":
+				soa.Expire = n
+			case "soa-min-ttl":
+				soa.MinTTL = n
+			case "soa-ttl":
+				soa.TTL = n
+			}
+			s.SOA = soa
+		}
+	default:
+		err = fmt.Errorf("unknown DNS setting %q (use %s)", name, strings.Join(DNSSettingNames, ", "))
+	}
+	if err != nil {
+		return DNSSettings{}, err
+	}
+	return s, s.Validate()
+}
+
+// Validate checks the set fields against the API's limits.
+func (s DNSSettings) Validate() error {
+	var problems []string
+	inRange := func(field string, v, lo, hi int) {
+		if v < lo || v > hi {
+			problems = append(problems, fmt.Sprintf("%s %d is out of range (%d-%d)", field, v, lo, hi))
+		}
+	}
+	// Zero SOA fields are unset.
+	check := func(field string, v, lo, hi int) {
+		if v != 0 {
+			inRange(field, v, lo, hi)
+		}
+	}
+	if s.NSTTL != nil {
+		inRange("ns_ttl", *s.NSTTL, 30, 86400)
+	}
+	if soa := s.SOA; soa != nil {
+		check("soa.refresh", soa.Refresh, 600, 86400)
+		check("soa.retry", soa.Retry, 600, 86400)
+		check("soa.expire", soa.Expire, 86400, 2419200)
+		check("soa.min_ttl", soa.MinTTL, 60, 86400)
+		check("soa.ttl", soa.TTL, 300, 86400)
+		if soa.RName != "" && strings.Contains(soa.RName, "@") {
+			problems = append(problems, fmt.Sprintf("soa.rname %q must be written as a domain name, e.g. hostmaster.example.com", soa.RName))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid DNS settings: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// PlanDNSSettings returns the update that brings current to the fields set
+// in desired, and a description of each change. SOA fields not set in
+// desired keep their current values.
+func PlanDNSSettings(current, desired DNSSettings) (DNSSettings, []string) {
+	var update DNSSettings
+	var changes []string
+	change := func(field, old, new string) {
+		changes = append(changes, fmt.Sprintf("%s: %s → %s", field, old, new))
+	}
+
+	boolField := func(field string, cur, want *bool, dst **bool) {
+		if want != nil && (cur == nil || *cur != *want) {
+			*dst = want
+			change(field, FormatOnOff(cur), FormatOnOff(want))
+		}
+	}
+	boolField("flatten_all_cnames", current.FlattenAllCNAMEs, desired.FlattenAllCNAMEs, &update.FlattenAllCNAMEs)
+	boolField("multi_provider", current.MultiProvider, desired.MultiProvider, &update.MultiProvider)
+	boolField("multi_signer_dnssec", current.MultiSigner, desired.MultiSigner, &update.MultiSigner)
+	boolField("secondary_overrides", current.SecondaryOverrides, desired.SecondaryOverrides, &update.SecondaryOverrides)
+
+	if want := desired.NSTTL; want != nil && (current.NSTTL == nil || *current.NSTTL != *want) {
+		update.NSTTL = want
+		old := "-"
+		if current.NSTTL != nil {
+			old = strconv.Itoa(*current.NSTTL)
+		}
+		change("ns_ttl", old, strconv.Itoa(*want))
+	}
+
+	if desired.SOA != nil {
+		var cur SOA
+		if current.SOA != nil {
+			cur = *current.SOA
+		}
+		merged := cur
+		d := desired.SOA
+		setString := func(field string, dst *string, want string) {
+			if want != "" && want != *dst {
+				old := "-"
+				if *dst != "" {
+					old = *dst
+				}
+				change("soa."+field, old, want)
+				*dst = want
+			}
+		}
+		setInt := func(field string, dst *int, want int) {
+			if want != 0 && want != *dst {
+				change("soa."+field, strconv.Itoa(*dst), strconv.Itoa(want))
+				*dst = want
+			}
+		}
+		setString("mname", &merged.MName, d.MName)
+		setString("rname", &merged.RName, d.RName)
+		setInt("refresh", &merged.Refresh, d.Refresh)
+		setInt("retry", &merged.Retry, d.Retry)
+		setInt("expire", &merged.Expire, d.Expire)
+		setInt("min_ttl", &merged.MinTTL, d.MinTTL)
+		setInt("ttl", &merged.TTL, d.TTL)
+		if merged != cur {
+			update.SOA = &merged
+		}
+	}
+
+	sort.Strings(changes)
+	return update, changes
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// FormatOnOff formats an on/off setting, with "-" when it is unset.
+func FormatOnOff(b *bool) string {
+	switch {
+	case b == nil:
+		return "-"
+	case *b:
+		return "on"
+	}
+	return "off"
+}
+
+// DNSSettings returns the current zone's DNS settings, including whether
+// multi-signer DNSSEC is allowed.
+func (c *Client) DNSSettings(ctx context.Context) (*DNSSettings, error) {
+	if c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	}
+	res, err := c.api.Raw(ctx, http.MethodGet, "/zones/"+c.zoneID+"/dns_settings", nil, nil)
+	if err != nil {
+		return nil, apiError(err, "get DNS settings", PermDNSRead)
+	}
+	var s DNSSettings
+	if err := json.Unmarshal(res.Result, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse DNS settings: %w", err)
+	}
+
+	res, err = c.api.Raw(ctx, http.MethodGet, "/zones/"+c.zoneID+"/dnssec", nil, nil)
+	if err != nil {
+		return nil, apiError(err, "get DNSSEC status", PermZoneSettingsRead)
+	}
+	var d struct {
+		MultiSigner bool `json:"dnssec_multi_signer"`
+	}
+	if err := json.Unmarshal(res.Result, &d); err != nil {
+		return nil, fmt.Errorf("failed to parse DNSSEC status: %w", err)
+	}
+	s.MultiSigner = &d.MultiSigner
+	return &s, nil
+}
+
+// UpdateDNSSettings changes the set fields of s for the current zone. The
+// SOA, when set, must be complete; see PlanDNSSettings.
+func (c *Client) UpdateDNSSettings(ctx context.Context, s DNSSettings) error {
+	if c.zoneID == "" {
+		return fmt.Errorf("zone not set")
+	}
+	multiSigner := s.MultiSigner
+	s.MultiSigner = nil
+	if s != (DNSSettings{}) {
+		if _, err := c.api.Raw(ctx, http.MethodPatch, "/zones/"+c.zoneID+"/dns_settings", s, nil); err != nil {
+			return apiError(err, "update DNS settings", PermDNSWrite)
+		}
+	}
+	if multiSigner != nil {
+		body := map[string]bool{"dnssec_multi_signer": *multiSigner}
+		if _, err := c.api.Raw(ctx, http.MethodPatch, "/zones/"+c.zoneID+"/dnssec", body, nil); err != nil {
+			return apiError(err, "update DNSSEC", PermZoneSettingsWrite)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package cloudflare
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDNSSetting(t *testing.T) {
+	tests := []struct {
+		name, value string
+		wantErr     bool
+	}{
+		{"cname-flattening", "all", false},
+		{"cname_flattening", "root", false},
+		{"cname-flattening", "none", true},
+		{"ns-ttl", "3600", false},
+		{"ns-ttl", "10", true},
+		{"ns-ttl", "0", true},
+		{"multi-signer", "on", false},
+		{"secondary-overrides", "maybe", true},
+		{"soa-expire", "604800", false},
+		{"soa-retry", "60", true},
+		{"soa-rname", "hostmaster@example.com", true},
+		{"soa-serial", "1", true},
+	}
+	for _, tt := range tests {
+		_, err := ParseDNSSetting(tt.name, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDNSSetting(%q, %q) error = %v, wantErr %v", tt.name, tt.value, err, tt.wantErr)
+		}
+	}
+
+	s, _ := ParseDNSSetting("cname-flattening", "all")
+	if s.FlattenAllCNAMEs == nil || !*s.FlattenAllCNAMEs {
+		t.Errorf("cname-flattening all: got %v", s.FlattenAllCNAMEs)
+	}
+}
+
+func TestPlanDNSSettings(t *testing.T) {
+	ttl := 86400
+	current := DNSSettings{
+		FlattenAllCNAMEs: boolPtr(false),
+		NSTTL:            &ttl,
+		MultiSigner:      boolPtr(false),
+		SOA:              &SOA{MName: "kim.ns.cloudflare.com", RName: "dns.cloudflare.com", Refresh: 10000, Retry: 2400, Expire: 604800, MinTTL: 1800, TTL: 3600},
+	}
+
+	same := 86400
+	update, changes := PlanDNSSettings(current, DNSSettings{NSTTL: &same, FlattenAllCNAMEs: boolPtr(false)})
+	if len(changes) != 0 || update != (DNSSettings{}) {
+		t.Errorf("unchanged settings: got %v, %+v", changes, update)
+	}
+
+	update, changes = PlanDNSSettings(current, DNSSettings{
+		FlattenAllCNAMEs: boolPtr(true),
+		MultiSigner:      boolPtr(true),
+		SOA:              &SOA{RName: "hostmaster.example.com", MinTTL: 300},
+	})
+	want := "flatten_all_cnames: off → on|multi_signer_dnssec: off → on|soa.min_ttl: 1800 → 300|soa.rname: dns.cloudflare.com → hostmaster.example.com"
+	if got := strings.Join(changes, "|"); got != want {
+		t.Errorf("changes:\ngot  %s\nwant %s", got, want)
+	}
+	if update.SOA == nil || update.SOA.Expire != 604800 || update.SOA.RName != "hostmaster.example.com" {
+		t.Errorf("SOA update should keep unchanged fields: %+v", update.SOA)
+	}
+	if update.NSTTL != nil {
+		t.Errorf("NSTTL should not be updated")
+	}
+}
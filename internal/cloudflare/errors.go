@@ -0,0 +1,75 @@
+package cloudflare
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Permission group names as shown by the Cloudflare API and dashboard.
+const (
+	PermZoneRead  = "Zone Read"
+	PermZoneWrite = "Zone Write"
+	PermDNSRead   = "DNS Read"
+	PermDNSWrite  = "DNS Write"
+
+	PermAPITokensRead  = "API Tokens Read"
+	PermAPITokensWrite = "API Tokens Write"
+	PermCachePurge     = "Cache Purge"
+)
+
+// ErrRateLimited is wrapped by errors for requests the API kept rejecting
+// with HTTP 429 after the client's own retries.
+var ErrRateLimited = errors.New("rate limited by the Cloudflare API")
+
+// apiError wraps an API error with the action that failed. Authentication
+// and authorization failures are rewritten to say what is wrong with the
+// credentials, naming the permission the action requires.
+func apiError(err error, action, permission string) error {
+	var authz *cloudflare.AuthorizationError
+	if errors.As(err, &authz) {
+		if permission == "" {
+			return fmt.Errorf("failed to %s: permission denied: %w", action, err)
+		}
+		return fmt.Errorf("failed to %s: permission denied, the API token needs the %q permission (check with 'cfcli auth verify'): %w", action, permission, err)
+	}
+	var authn *cloudflare.AuthenticationError
+	if errors.As(err, &authn) {
+		return fmt.Errorf("failed to %s: authentication failed, the API token is invalid, expired or revoked: %w", action, err)
+	}
+	var limited *cloudflare.RatelimitError
+	if errors.As(err, &limited) || strings.Contains(err.Error(), "exceeded available rate limit retries") {
+		return fmt.Errorf("failed to %s: %w: %w", action, ErrRateLimited, err)
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+// rulesError is apiError for requests that write rules. When the API
+// rejects the rules themselves, typically for an invalid expression, its
+// messages are returned one per line, as they often point at the offending
+// part of the expression.
+func rulesError(err error, action, permission string) error {
+	var invalid *cloudflare.RequestError
+	if errors.As(err, &invalid) && len(invalid.ErrorMessages()) > 0 {
+		return &rejectedRulesError{action: action, messages: invalid.ErrorMessages(), err: err}
+	}
+	return apiError(err, action, permission)
+}
+
+// rejectedRulesError lists the API's messages about rejected rules and
+// wraps the API error, whose own message would repeat them on one line.
+type rejectedRulesError struct {
+	action   string
+	messages []string
+	err      error
+}
+
+func (e *rejectedRulesError) Error() string {
+	return fmt.Sprintf("failed to %s, the API rejected the rule:\n  %s", e.action, strings.Join(e.messages, "\n  "))
+}
+
+func (e *rejectedRulesError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,64 @@
+package cloudflare
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func TestAPIError(t *testing.T) {
+	cfErr := &cloudflare.Error{StatusCode: 403}
+	authz := cloudflare.NewAuthorizationError(cfErr)
+	authn := cloudflare.NewAuthenticationError(cfErr)
+
+	tests := []struct {
+		name     string
+		err      error
+		contains string
+	}{
+		{"authorization", &authz, `needs the "DNS Write" permission`},
+		{"authentication", &authn, "authentication failed"},
+		{"rate limited", errors.New("exceeded available rate limit retries"), "rate limited"},
+		{"other", errors.New("boom"), "failed to create DNS record: boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := apiError(tt.err, "create DNS record", PermDNSWrite)
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("apiError() = %q, want it to contain %q", err, tt.contains)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("apiError() should wrap the original error")
+			}
+		})
+	}
+}
+
+func TestAPIErrorRateLimited(t *testing.T) {
+	err := apiError(errors.New("exceeded available rate limit retries"), "purge cache", PermCachePurge)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("apiError() = %v, want it to wrap ErrRateLimited", err)
+	}
+}
+
+func TestRulesError(t *testing.T) {
+	cfErr := &cloudflare.Error{StatusCode: 400, ErrorMessages: []string{"Filter parsing error (1:11):\nip.src eq 1.2.3\n          ^^^^^"}}
+	invalid := cloudflare.NewRequestError(cfErr)
+	err := rulesError(&invalid, "update custom rules", PermWAFWrite)
+	if !strings.Contains(err.Error(), "rejected the rule:\n  Filter parsing error") {
+		t.Errorf("rulesError() = %q", err)
+	}
+	var reqErr *cloudflare.RequestError
+	if !errors.As(err, &reqErr) {
+		t.Errorf("rulesError() = %v, want it to wrap the RequestError", err)
+	}
+
+	cfErr = &cloudflare.Error{StatusCode: 403}
+	authn := cloudflare.NewAuthenticationError(cfErr)
+	if err := rulesError(&authn, "update custom rules", PermWAFWrite); !strings.Contains(err.Error(), "authentication failed") {
+		t.Errorf("rulesError() = %q, want the apiError message", err)
+	}
+}
@@ -0,0 +1,335 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Permission group names for firewall rules.
+const (
+	PermWAFRead  = "Zone WAF Read"
+	PermWAFWrite = "Zone WAF Write"
+
+	PermAccessRulesRead         = "Firewall Services Read"
+	PermAccessRulesWrite        = "Firewall Services Write"
+	PermAccountAccessRulesRead  = "Account Firewall Access Rules Read"
+	PermAccountAccessRulesWrite = "Account Firewall Access Rules Write"
+)
+
+// PhaseFirewallCustom is the ruleset phase of WAF custom rules.
+const PhaseFirewallCustom = "http_request_firewall_custom"
+
+// FirewallActions are the actions a custom rule can be added with.
+var FirewallActions = []string{"block", "managed_challenge", "js_challenge", "challenge", "log"}
+
+// FirewallRule is a WAF custom rule.
+type FirewallRule struct {
+	ID          string `json:"id,omitempty"`
+	Description string `json:"description,omitempty"`
+	Expression  string `json:"expression"`
+	Action      string `json:"action"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// CheckExpression catches mistakes in a rule expression that the API
+// would reject with a less helpful message: an empty expression and
+// unbalanced quotes or parentheses.
+func CheckExpression(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return fmt.Errorf("expression is empty")
+	}
+	depth := 0
+	inString := false
+	for i := 0; i < len(expr); i++ {
+		switch ch := expr[i]; {
+		case inString && ch == '\\':
+			i++
+		case ch == '"':
+			inString = !inString
+		case inString:
+		case ch == '(':
+			depth++
+		case ch == ')':
+			if depth--; depth < 0 {
+				return fmt.Errorf("expression has an unmatched ) at position %d", i+1)
+			}
+		}
+	}
+	if inString {
+		return fmt.Errorf("expression has an unterminated string")
+	}
+	if depth > 0 {
+		return fmt.Errorf("expression has %d unclosed (", depth)
+	}
+	return nil
+}
+
+// FirewallRules returns the current zone's custom rules in evaluation
+// order.
+func (c *Client) FirewallRules(ctx context.Context) ([]FirewallRule, error) {
+	rs, err := c.entrypointRules(ctx, PhaseFirewallCustom, "list custom rules", PermWAFRead)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]FirewallRule, 0, len(rs))
+	for _, r := range rs {
+		rules = append(rules, firewallRuleFrom(r))
+	}
+	return rules, nil
+}
+
+// AddFirewallRule adds a custom rule to the current zone, after the
+// existing rules or, when first is set, before them.
+func (c *Client) AddFirewallRule(ctx context.Context, rule FirewallRule, first bool) error {
+	existing, err := c.entrypointRules(ctx, PhaseFirewallCustom, "list custom rules", PermWAFRead)
+	if err != nil {
+		return err
+	}
+	enabled := rule.Enabled
+	r := cloudflare.RulesetRule{
+		Action:      rule.Action,
+		Expression:  rule.Expression,
+		Description: rule.Description,
+		Enabled:     &enabled,
+	}
+	if first {
+		existing = append([]cloudflare.RulesetRule{r}, existing...)
+	} else {
+		existing = append(existing, r)
+	}
+	return c.putEntrypointRules(ctx, PhaseFirewallCustom, existing, "add custom rule", PermWAFWrite)
+}
+
+// SetFirewallRuleEnabled enables or disables the custom rule with the
+// given ID.
+func (c *Client) SetFirewallRuleEnabled(ctx context.Context, id string, enabled bool) error {
+	return c.updateFirewallRules(ctx, id, func(rules []cloudflare.RulesetRule, i int) []cloudflare.RulesetRule {
+		rules[i].Enabled = &enabled
+		return rules
+	})
+}
+
+// DeleteFirewallRule removes the custom rule with the given ID.
+func (c *Client) DeleteFirewallRule(ctx context.Context, id string) error {
+	return c.updateFirewallRules(ctx, id, func(rules []cloudflare.RulesetRule, i int) []cloudflare.RulesetRule {
+		return append(rules[:i], rules[i+1:]...)
+	})
+}
+
+func (c *Client) updateFirewallRules(ctx context.Context, id string, update func([]cloudflare.RulesetRule, int) []cloudflare.RulesetRule) error {
+	existing, err := c.entrypointRules(ctx, PhaseFirewallCustom, "list custom rules", PermWAFRead)
+	if err != nil {
+		return err
+	}
+	for i, r := range existing {
+		if r.ID == id {
+			return c.putEntrypointRules(ctx, PhaseFirewallCustom, update(existing, i), "update custom rules", PermWAFWrite)
+		}
+	}
+	return fmt.Errorf("no custom rule with ID %s", id)
+}
+
+func firewallRuleFrom(r cloudflare.RulesetRule) FirewallRule {
+	return FirewallRule{
+		ID:          r.ID,
+		Description: r.Description,
+		Expression:  r.Expression,
+		Action:      r.Action,
+		Enabled:     r.Enabled == nil || *r.Enabled,
+	}
+}
+
+// AccessRule is an IP access rule, applying to a zone or to all zones of
+// an account.
+type AccessRule struct {
+	ID string `json:"id"`
+	// Mode is block, challenge, managed_challenge, js_challenge or
+	// whitelist.
+	Mode string `json:"mode"`
+	// Target is ip, ip6, ip_range, asn or country.
+	Target    string    `json:"target"`
+	Value     string    `json:"value"`
+	Notes     string    `json:"notes,omitempty"`
+	Scope     string    `json:"scope"`
+	CreatedOn time.Time `json:"created_on"`
+}
+
+var asnRE = regexp.MustCompile(`^(?i:as)?(\d+)$`)
+
+// ParseAccessTarget works out what an IP access rule value is: an IPv4 or
+// IPv6 address, a CIDR range (/16 or /24 for IPv4, /32, /48 or /64 for
+// IPv6), an ASN such as AS13335, or a two-letter country code. It returns
+// the target and the value in the form the API expects.
+func ParseAccessTarget(s string) (target, value string, err error) {
+	s = strings.TrimSpace(s)
+	if addr, err := netip.ParseAddr(s); err == nil {
+		if addr.Is4() || addr.Is4In6() {
+			return "ip", addr.Unmap().String(), nil
+		}
+		return "ip6", addr.String(), nil
+	}
+	if prefix, err := netip.ParsePrefix(s); err == nil {
+		bits := prefix.Bits()
+		switch {
+		case prefix.Addr().Is4() && (bits == 16 || bits == 24):
+		case prefix.Addr().Is6() && (bits == 32 || bits == 48 || bits == 64):
+		default:
+			return "", "", fmt.Errorf("unsupported range %s: IP access rules take IPv4 /16 or /24 and IPv6 /32, /48 or /64 ranges; match other ranges with a custom rule (ip.src in {%s})", s, prefix.Masked())
+		}
+		return "ip_range", prefix.Masked().String(), nil
+	}
+	if m := asnRE.FindStringSubmatch(s); m != nil {
+		return "asn", "AS" + m[1], nil
+	}
+	if len(s) == 2 && isLetters(s) {
+		return "country", strings.ToUpper(s), nil
+	}
+	return "", "", fmt.Errorf("%q is not an IP address, CIDR range, ASN (AS13335) or country code (CN)", s)
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// AccessRules returns the IP access rules of the account with the given ID,
+// or when accountID is empty those applying to the current zone, including
+// the ones inherited from its account.
+func (c *Client) AccessRules(ctx context.Context, accountID string) ([]AccessRule, error) {
+	return c.findAccessRules(ctx, accountID, cloudflare.AccessRule{})
+}
+
+// FindAccessRule returns the IP access rule for value set in the account
+// with the given ID, or in the current zone when accountID is empty, or nil
+// if there is none. Account rules a zone inherits are not returned for it.
+func (c *Client) FindAccessRule(ctx context.Context, accountID, value string) (*AccessRule, error) {
+	scope := "zone"
+	if accountID != "" {
+		scope = "account"
+	}
+	filter := cloudflare.AccessRule{Configuration: cloudflare.AccessRuleConfiguration{Value: value}}
+	rules, err := c.findAccessRules(ctx, accountID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		if strings.EqualFold(r.Value, value) && (r.Scope == "" || r.Scope == scope) {
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Client) findAccessRules(ctx context.Context, accountID string, filter cloudflare.AccessRule) ([]AccessRule, error) {
+	if accountID == "" && c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	}
+	var rules []AccessRule
+	for page := 1; ; page++ {
+		var res *cloudflare.AccessRuleListResponse
+		var err error
+		if accountID != "" {
+			res, err = c.api.ListAccountAccessRules(ctx, accountID, filter, page)
+		} else {
+			res, err = c.api.ListZoneAccessRules(ctx, c.zoneID, filter, page)
+		}
+		if err != nil {
+			return nil, apiError(err, "list IP access rules", accessRulesPermission(accountID, false))
+		}
+		for _, r := range res.Result {
+			rules = append(rules, accessRuleFrom(r))
+		}
+		if page >= res.TotalPages {
+			return rules, nil
+		}
+	}
+}
+
+// CreateAccessRule creates an IP access rule in the account with the given
+// ID, or in the current zone when accountID is empty.
+func (c *Client) CreateAccessRule(ctx context.Context, accountID string, rule AccessRule) (*AccessRule, error) {
+	req := cloudflare.AccessRule{
+		Mode:          rule.Mode,
+		Notes:         rule.Notes,
+		Configuration: cloudflare.AccessRuleConfiguration{Target: rule.Target, Value: rule.Value},
+	}
+	var res *cloudflare.AccessRuleResponse
+	var err error
+	if accountID != "" {
+		res, err = c.api.CreateAccountAccessRule(ctx, accountID, req)
+	} else if c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	} else {
+		res, err = c.api.CreateZoneAccessRule(ctx, c.zoneID, req)
+	}
+	if err != nil {
+		return nil, apiError(err, "create IP access rule", accessRulesPermission(accountID, true))
+	}
+	created := accessRuleFrom(res.Result)
+	return &created, nil
+}
+
+// UpdateAccessRule changes the mode and notes of an IP access rule.
+// accountID selects the scope as for CreateAccessRule.
+func (c *Client) UpdateAccessRule(ctx context.Context, accountID, id, mode, notes string) error {
+	req := cloudflare.AccessRule{Mode: mode, Notes: notes}
+	var err error
+	if accountID != "" {
+		_, err = c.api.UpdateAccountAccessRule(ctx, accountID, id, req)
+	} else {
+		_, err = c.api.UpdateZoneAccessRule(ctx, c.zoneID, id, req)
+	}
+	if err != nil {
+		return apiError(err, "update IP access rule", accessRulesPermission(accountID, true))
+	}
+	return nil
+}
+
+// DeleteAccessRule deletes an IP access rule. accountID selects the scope
+// as for CreateAccessRule.
+func (c *Client) DeleteAccessRule(ctx context.Context, accountID, id string) error {
+	var err error
+	if accountID != "" {
+		_, err = c.api.DeleteAccountAccessRule(ctx, accountID, id)
+	} else {
+		_, err = c.api.DeleteZoneAccessRule(ctx, c.zoneID, id)
+	}
+	if err != nil {
+		return apiError(err, "delete IP access rule", accessRulesPermission(accountID, true))
+	}
+	return nil
+}
+
+func accessRulesPermission(accountID string, write bool) string {
+	switch {
+	case accountID != "" && write:
+		return PermAccountAccessRulesWrite
+	case accountID != "":
+		return PermAccountAccessRulesRead
+	case write:
+		return PermAccessRulesWrite
+	}
+	return PermAccessRulesRead
+}
+
+func accessRuleFrom(r cloudflare.AccessRule) AccessRule {
+	return AccessRule{
+		ID:        r.ID,
+		Mode:      r.Mode,
+		Target:    r.Configuration.Target,
+		Value:     r.Configuration.Value,
+		Notes:     r.Notes,
+		Scope:     r.Scope.Type,
+		CreatedOn: r.CreatedOn,
+	}
+}
@@ -0,0 +1,51 @@
+package cloudflare
+
+import "testing"
+
+func TestCheckExpression(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{`(ip.src in {192.0.2.0/24}) and http.request.uri.path eq "/login"`, false},
+		{`http.request.uri.path contains "(" and (ip.geoip.country eq "CN")`, false},
+		{`http.user_agent contains "\"x\""`, false},
+		{"", true},
+		{`(ip.src eq 192.0.2.1`, true},
+		{`ip.src eq 192.0.2.1)`, true},
+		{`http.host eq "example.com`, true},
+	}
+	for _, tt := range tests {
+		if err := CheckExpression(tt.expr); (err != nil) != tt.wantErr {
+			t.Errorf("CheckExpression(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseAccessTarget(t *testing.T) {
+	tests := []struct {
+		in            string
+		target, value string
+		wantErr       bool
+	}{
+		{"192.0.2.1", "ip", "192.0.2.1", false},
+		{"2001:db8::1", "ip6", "2001:db8::1", false},
+		{"192.0.2.7/24", "ip_range", "192.0.2.0/24", false},
+		{"2001:db8::/48", "ip_range", "2001:db8::/48", false},
+		{"AS13335", "asn", "AS13335", false},
+		{"13335", "asn", "AS13335", false},
+		{"cn", "country", "CN", false},
+		{"192.0.2.0/28", "", "", true},
+		{"example.com", "", "", true},
+	}
+	for _, tt := range tests {
+		target, value, err := ParseAccessTarget(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAccessTarget(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if target != tt.target || value != tt.value {
+			t.Errorf("ParseAccessTarget(%q) = %s, %s, want %s, %s", tt.in, target, value, tt.target, tt.value)
+		}
+	}
+}
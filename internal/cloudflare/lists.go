@@ -0,0 +1,272 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Permission group names for account lists.
+const (
+	PermListsRead  = "Account Filter Lists Read"
+	PermListsWrite = "Account Filter Lists Edit"
+)
+
+// List kinds.
+const (
+	ListKindIP       = "ip"
+	ListKindRedirect = "redirect"
+)
+
+// ListBatchSize is the number of items cfcli sends in one list request.
+const ListBatchSize = 1000
+
+// listOperationTimeout bounds the wait for one bulk list operation.
+const listOperationTimeout = 10 * time.Minute
+
+// List is an account-level list of IPs, redirects, hostnames or ASNs.
+type List struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Kind        string `json:"kind"`
+	NumItems    int    `json:"num_items"`
+}
+
+// ListItem is an item of a list. Exactly one of IP and Redirect is set,
+// matching the list's kind.
+type ListItem struct {
+	ID       string        `json:"id,omitempty"`
+	IP       string        `json:"ip,omitempty"`
+	Redirect *ListRedirect `json:"redirect,omitempty"`
+	Comment  string        `json:"comment,omitempty"`
+}
+
+// ListRedirect is the redirect of an item in a redirect list.
+type ListRedirect struct {
+	SourceURL           string `json:"source_url"`
+	TargetURL           string `json:"target_url"`
+	StatusCode          int    `json:"status_code"`
+	IncludeSubdomains   bool   `json:"include_subdomains"`
+	SubpathMatching     bool   `json:"subpath_matching"`
+	PreserveQueryString bool   `json:"preserve_query_string"`
+	PreservePathSuffix  bool   `json:"preserve_path_suffix"`
+}
+
+// Key identifies the item within its list: the IP or the redirect's
+// source URL.
+func (i ListItem) Key() string {
+	if i.Redirect != nil {
+		return i.Redirect.SourceURL
+	}
+	return i.IP
+}
+
+// sameItem reports whether a and b have the same contents, ignoring IDs.
+func sameItem(a, b ListItem) bool {
+	if a.IP != b.IP || a.Comment != b.Comment || (a.Redirect == nil) != (b.Redirect == nil) {
+		return false
+	}
+	return a.Redirect == nil || *a.Redirect == *b.Redirect
+}
+
+// DiffListItems returns the items to add and remove to bring existing in
+// line with desired. Items whose contents changed are removed and added
+// again, as lists allow only one item per key. Items missing from desired
+// are only removed when prune is set.
+func DiffListItems(existing, desired []ListItem, prune bool) (add, remove []ListItem) {
+	current := make(map[string]ListItem, len(existing))
+	for _, item := range existing {
+		current[item.Key()] = item
+	}
+	wanted := make(map[string]bool, len(desired))
+	for _, item := range desired {
+		wanted[item.Key()] = true
+		old, ok := current[item.Key()]
+		switch {
+		case !ok:
+			add = append(add, item)
+		case !sameItem(old, item):
+			remove = append(remove, old)
+			add = append(add, item)
+		}
+	}
+	if prune {
+		for _, item := range existing {
+			if !wanted[item.Key()] {
+				remove = append(remove, item)
+			}
+		}
+	}
+	return add, remove
+}
+
+// Lists returns the account's lists.
+func (c *Client) Lists(ctx context.Context, accountID string) ([]List, error) {
+	ls, err := c.api.ListLists(ctx, cloudflare.AccountIdentifier(accountID), cloudflare.ListListsParams{})
+	if err != nil {
+		return nil, apiError(err, "list lists", PermListsRead)
+	}
+	lists := make([]List, 0, len(ls))
+	for _, l := range ls {
+		lists = append(lists, List{ID: l.ID, Name: l.Name, Description: l.Description, Kind: l.Kind, NumItems: l.NumItems})
+	}
+	return lists, nil
+}
+
+// ListByName returns the account's list with the given name, or nil if
+// there is none.
+func (c *Client) ListByName(ctx context.Context, accountID, name string) (*List, error) {
+	lists, err := c.Lists(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lists {
+		if l.Name == name {
+			return &l, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateList creates an empty list of the given kind. Names may only
+// contain lowercase letters, digits and underscores.
+func (c *Client) CreateList(ctx context.Context, accountID, name, kind, description string) (*List, error) {
+	params := cloudflare.ListCreateParams{Name: name, Kind: kind, Description: description}
+	l, err := c.api.CreateList(ctx, cloudflare.AccountIdentifier(accountID), params)
+	if err != nil {
+		return nil, apiError(err, "create list "+name, PermListsWrite)
+	}
+	return &List{ID: l.ID, Name: l.Name, Description: l.Description, Kind: l.Kind}, nil
+}
+
+// ListItems returns all items of a list.
+func (c *Client) ListItems(ctx context.Context, accountID, listID string) ([]ListItem, error) {
+	params := cloudflare.ListListItemsParams{ID: listID, PerPage: 500}
+	items, err := c.api.ListListItems(ctx, cloudflare.AccountIdentifier(accountID), params)
+	if err != nil {
+		return nil, apiError(err, "list items", PermListsRead)
+	}
+	result := make([]ListItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, listItemFrom(item))
+	}
+	return result, nil
+}
+
+// AddListItems adds items to a list and waits for the API to finish
+// processing them. Use at most ListBatchSize items per call.
+func (c *Client) AddListItems(ctx context.Context, accountID, listID string, items []ListItem) error {
+	reqs := make([]cloudflare.ListItemCreateRequest, 0, len(items))
+	for _, item := range items {
+		reqs = append(reqs, item.createRequest())
+	}
+	rc := cloudflare.AccountIdentifier(accountID)
+	res, err := c.api.CreateListItemsAsync(ctx, rc, cloudflare.ListCreateItemsParams{ID: listID, Items: reqs})
+	if err != nil {
+		return apiError(err, "add list items", PermListsWrite)
+	}
+	return c.waitListOperation(ctx, accountID, res.Result.OperationID)
+}
+
+// ReplaceListItems replaces all items of a list with items in a single
+// operation and waits for the API to finish processing it.
+func (c *Client) ReplaceListItems(ctx context.Context, accountID, listID string, items []ListItem) error {
+	reqs := make([]cloudflare.ListItemCreateRequest, 0, len(items))
+	for _, item := range items {
+		reqs = append(reqs, item.createRequest())
+	}
+	rc := cloudflare.AccountIdentifier(accountID)
+	res, err := c.api.ReplaceListItemsAsync(ctx, rc, cloudflare.ListReplaceItemsParams{ID: listID, Items: reqs})
+	if err != nil {
+		return apiError(err, "replace list items", PermListsWrite)
+	}
+	return c.waitListOperation(ctx, accountID, res.Result.OperationID)
+}
+
+// DeleteListItems removes items, by ID, from a list and waits for the API
+// to finish processing them.
+func (c *Client) DeleteListItems(ctx context.Context, accountID, listID string, ids []string) error {
+	var req cloudflare.ListItemDeleteRequest
+	for _, id := range ids {
+		req.Items = append(req.Items, cloudflare.ListItemDeleteItemRequest{ID: id})
+	}
+	rc := cloudflare.AccountIdentifier(accountID)
+	res, err := c.api.DeleteListItemsAsync(ctx, rc, cloudflare.ListDeleteItemsParams{ID: listID, Items: req})
+	if err != nil {
+		return apiError(err, "delete list items", PermListsWrite)
+	}
+	return c.waitListOperation(ctx, accountID, res.Result.OperationID)
+}
+
+// waitListOperation polls a bulk list operation until it completes, backing
+// off from half a second to five seconds between polls.
+func (c *Client) waitListOperation(ctx context.Context, accountID, operationID string) error {
+	ctx, cancel := context.WithTimeout(ctx, listOperationTimeout)
+	defer cancel()
+
+	delay := 500 * time.Millisecond
+	for {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return fmt.Errorf("list operation %s did not finish: %w", operationID, ctx.Err())
+		}
+		op, err := c.api.GetListBulkOperation(ctx, cloudflare.AccountIdentifier(accountID), operationID)
+		if err != nil {
+			return apiError(err, "check list operation", PermListsRead)
+		}
+		switch op.Status {
+		case "completed":
+			return nil
+		case "failed":
+			return fmt.Errorf("list operation failed: %s", op.Error)
+		}
+		delay = min(2*delay, 5*time.Second)
+	}
+}
+
+func (i ListItem) createRequest() cloudflare.ListItemCreateRequest {
+	req := cloudflare.ListItemCreateRequest{Comment: i.Comment}
+	if i.IP != "" {
+		ip := i.IP
+		req.IP = &ip
+	}
+	if r := i.Redirect; r != nil {
+		status := r.StatusCode
+		req.Redirect = &cloudflare.Redirect{
+			SourceUrl:           r.SourceURL,
+			TargetUrl:           r.TargetURL,
+			StatusCode:          &status,
+			IncludeSubdomains:   boolPtr(r.IncludeSubdomains),
+			SubpathMatching:     boolPtr(r.SubpathMatching),
+			PreserveQueryString: boolPtr(r.PreserveQueryString),
+			PreservePathSuffix:  boolPtr(r.PreservePathSuffix),
+		}
+	}
+	return req
+}
+
+func listItemFrom(item cloudflare.ListItem) ListItem {
+	result := ListItem{ID: item.ID, Comment: item.Comment}
+	if item.IP != nil {
+		result.IP = *item.IP
+	}
+	if r := item.Redirect; r != nil {
+		result.Redirect = &ListRedirect{
+			SourceURL:           r.SourceUrl,
+			TargetURL:           r.TargetUrl,
+			StatusCode:          301,
+			IncludeSubdomains:   r.IncludeSubdomains != nil && *r.IncludeSubdomains,
+			SubpathMatching:     r.SubpathMatching != nil && *r.SubpathMatching,
+			PreserveQueryString: r.PreserveQueryString != nil && *r.PreserveQueryString,
+			PreservePathSuffix:  r.PreservePathSuffix != nil && *r.PreservePathSuffix,
+		}
+		if r.StatusCode != nil {
+			result.Redirect.StatusCode = *r.StatusCode
+		}
+	}
+	return result
+}
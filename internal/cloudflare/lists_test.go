@@ -0,0 +1,35 @@
+package cloudflare
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffListItems(t *testing.T) {
+	redirect := func(id, source, target string) ListItem {
+		return ListItem{ID: id, Redirect: &ListRedirect{SourceURL: source, TargetURL: target, StatusCode: 301}}
+	}
+	existing := []ListItem{
+		redirect("1", "example.com/a", "https://example.com/1"),
+		redirect("2", "example.com/b", "https://example.com/2"),
+		redirect("3", "example.com/c", "https://example.com/3"),
+	}
+	desired := []ListItem{
+		redirect("", "example.com/a", "https://example.com/1"),
+		redirect("", "example.com/b", "https://example.com/changed"),
+		redirect("", "example.com/d", "https://example.com/4"),
+	}
+
+	add, remove := DiffListItems(existing, desired, false)
+	if want := desired[1:]; !reflect.DeepEqual(add, want) {
+		t.Errorf("add = %+v, want %+v", add, want)
+	}
+	if want := existing[1:2]; !reflect.DeepEqual(remove, want) {
+		t.Errorf("remove = %+v, want %+v", remove, want)
+	}
+
+	_, remove = DiffListItems(existing, desired, true)
+	if want := existing[1:]; !reflect.DeepEqual(remove, want) {
+		t.Errorf("remove with prune = %+v, want %+v", remove, want)
+	}
+}
@@ -0,0 +1,69 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// PurgeBatchSize is the number of URLs, tags, hosts or prefixes the API
+// accepts in one purge request on every plan. Enterprise zones accept more.
+const PurgeBatchSize = 30
+
+// PurgeRequest selects what to purge from the current zone's cache.
+type PurgeRequest struct {
+	Everything bool
+	URLs       []string
+	Tags       []string
+	Hosts      []string
+	Prefixes   []string
+}
+
+// Len returns the number of items the request purges.
+func (r PurgeRequest) Len() int {
+	return len(r.URLs) + len(r.Tags) + len(r.Hosts) + len(r.Prefixes)
+}
+
+// Batches splits r into requests of at most size items. Each batch purges
+// items of one kind only, as the API requires.
+func (r PurgeRequest) Batches(size int) []PurgeRequest {
+	if r.Everything {
+		return []PurgeRequest{{Everything: true}}
+	}
+	if size <= 0 {
+		size = PurgeBatchSize
+	}
+	var batches []PurgeRequest
+	split := func(items []string, wrap func([]string) PurgeRequest) {
+		for len(items) > 0 {
+			n := min(size, len(items))
+			batches = append(batches, wrap(items[:n]))
+			items = items[n:]
+		}
+	}
+	split(r.URLs, func(s []string) PurgeRequest { return PurgeRequest{URLs: s} })
+	split(r.Tags, func(s []string) PurgeRequest { return PurgeRequest{Tags: s} })
+	split(r.Hosts, func(s []string) PurgeRequest { return PurgeRequest{Hosts: s} })
+	split(r.Prefixes, func(s []string) PurgeRequest { return PurgeRequest{Prefixes: s} })
+	return batches
+}
+
+// PurgeCache sends one purge request for the current zone. Use Batches to
+// stay within the API's per-request limits.
+func (c *Client) PurgeCache(ctx context.Context, r PurgeRequest) error {
+	if c.zoneID == "" {
+		return fmt.Errorf("zone not set")
+	}
+	req := cloudflare.PurgeCacheRequest{
+		Everything: r.Everything,
+		Files:      r.URLs,
+		Tags:       r.Tags,
+		Hosts:      r.Hosts,
+		Prefixes:   r.Prefixes,
+	}
+	if _, err := c.api.PurgeCacheContext(ctx, c.zoneID, req); err != nil {
+		return apiError(err, "purge cache", PermCachePurge)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package cloudflare
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPurgeRequestBatches(t *testing.T) {
+	var urls []string
+	for i := 0; i < 65; i++ {
+		urls = append(urls, fmt.Sprintf("https://example.com/%d", i))
+	}
+	req := PurgeRequest{URLs: urls, Tags: []string{"a", "b"}}
+
+	batches := req.Batches(30)
+	if len(batches) != 4 {
+		t.Fatalf("got %d batches, want 4", len(batches))
+	}
+	sizes := []int{30, 30, 5, 2}
+	for i, b := range batches {
+		if b.Len() != sizes[i] {
+			t.Errorf("batch %d has %d items, want %d", i, b.Len(), sizes[i])
+		}
+	}
+	if len(batches[3].Tags) != 2 || len(batches[3].URLs) != 0 {
+		t.Errorf("last batch mixes kinds: %+v", batches[3])
+	}
+
+	if got := (PurgeRequest{Everything: true, URLs: urls}).Batches(30); len(got) != 1 || !got[0].Everything {
+		t.Errorf("purge everything should be a single request, got %+v", got)
+	}
+}
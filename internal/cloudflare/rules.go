@@ -0,0 +1,207 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Permission group names for rules.
+const (
+	PermPageRulesRead       = "Page Rules Read"
+	PermSingleRedirectWrite = "Single Redirect Write"
+	PermSingleRedirectRead  = "Single Redirect Read"
+)
+
+// PhaseDynamicRedirect is the ruleset phase of single redirect rules.
+const PhaseDynamicRedirect = "http_request_dynamic_redirect"
+
+// RedirectRule is a single redirect rule.
+type RedirectRule struct {
+	ID          string `json:"id,omitempty"`
+	Description string `json:"description,omitempty"`
+	Expression  string `json:"expression"`
+	Enabled     bool   `json:"enabled"`
+	// Exactly one of TargetURL and TargetExpression is set.
+	TargetURL        string `json:"target_url,omitempty"`
+	TargetExpression string `json:"target_expression,omitempty"`
+	StatusCode       int    `json:"status_code"`
+	PreserveQuery    bool   `json:"preserve_query_string"`
+}
+
+// RedirectRules returns the current zone's single redirect rules in
+// evaluation order.
+func (c *Client) RedirectRules(ctx context.Context) ([]RedirectRule, error) {
+	rs, err := c.entrypointRules(ctx, PhaseDynamicRedirect, "list redirect rules", PermSingleRedirectRead)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]RedirectRule, 0, len(rs))
+	for _, r := range rs {
+		rules = append(rules, redirectRuleFrom(r))
+	}
+	return rules, nil
+}
+
+// AddRedirectRules appends rules to the current zone's single redirect
+// rules.
+func (c *Client) AddRedirectRules(ctx context.Context, rules ...RedirectRule) error {
+	existing, err := c.entrypointRules(ctx, PhaseDynamicRedirect, "list redirect rules", PermSingleRedirectRead)
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		existing = append(existing, r.rulesetRule())
+	}
+	return c.putEntrypointRules(ctx, PhaseDynamicRedirect, existing, "update redirect rules", PermSingleRedirectWrite)
+}
+
+// DeleteRedirectRule removes the single redirect rule with the given ID.
+func (c *Client) DeleteRedirectRule(ctx context.Context, id string) error {
+	existing, err := c.entrypointRules(ctx, PhaseDynamicRedirect, "list redirect rules", PermSingleRedirectRead)
+	if err != nil {
+		return err
+	}
+	kept := existing[:0]
+	for _, r := range existing {
+		if r.ID != id {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == len(existing) {
+		return fmt.Errorf("no redirect rule with ID %s", id)
+	}
+	return c.putEntrypointRules(ctx, PhaseDynamicRedirect, kept, "update redirect rules", PermSingleRedirectWrite)
+}
+
+// entrypointRules returns the rules of the current zone's entry point
+// ruleset for phase.
+func (c *Client) entrypointRules(ctx context.Context, phase, action, permission string) ([]cloudflare.RulesetRule, error) {
+	if c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	}
+	rs, err := c.api.GetEntrypointRuleset(ctx, cloudflare.ZoneIdentifier(c.zoneID), phase)
+	if err != nil {
+		var notFound *cloudflare.NotFoundError
+		if errors.As(err, &notFound) {
+			// The phase has no entry point until its first rule is added.
+			return nil, nil
+		}
+		return nil, apiError(err, action, permission)
+	}
+	return rs.Rules, nil
+}
+
+// putEntrypointRules replaces the rules of the current zone's entry point
+// ruleset for phase, creating it if needed.
+func (c *Client) putEntrypointRules(ctx context.Context, phase string, rules []cloudflare.RulesetRule, action, permission string) error {
+	for i := range rules {
+		// Read-only fields the API rejects on update.
+		rules[i].Version = nil
+		rules[i].LastUpdated = nil
+	}
+	params := cloudflare.UpdateEntrypointRulesetParams{Phase: phase, Rules: rules}
+	if _, err := c.api.UpdateEntrypointRuleset(ctx, cloudflare.ZoneIdentifier(c.zoneID), params); err != nil {
+		return rulesError(err, action, permission)
+	}
+	return nil
+}
+
+func (r RedirectRule) rulesetRule() cloudflare.RulesetRule {
+	enabled := r.Enabled
+	preserve := r.PreserveQuery
+	return cloudflare.RulesetRule{
+		Action:      "redirect",
+		Expression:  r.Expression,
+		Description: r.Description,
+		Enabled:     &enabled,
+		ActionParameters: &cloudflare.RulesetRuleActionParameters{
+			FromValue: &cloudflare.RulesetRuleActionParametersFromValue{
+				StatusCode: uint16(r.StatusCode),
+				TargetURL: cloudflare.RulesetRuleActionParametersTargetURL{
+					Value:      r.TargetURL,
+					Expression: r.TargetExpression,
+				},
+				PreserveQueryString: &preserve,
+			},
+		},
+	}
+}
+
+func redirectRuleFrom(r cloudflare.RulesetRule) RedirectRule {
+	rule := RedirectRule{
+		ID:          r.ID,
+		Description: r.Description,
+		Expression:  r.Expression,
+		Enabled:     r.Enabled == nil || *r.Enabled,
+	}
+	if p := r.ActionParameters; p != nil && p.FromValue != nil {
+		rule.TargetURL = p.FromValue.TargetURL.Value
+		rule.TargetExpression = p.FromValue.TargetURL.Expression
+		rule.StatusCode = int(p.FromValue.StatusCode)
+		rule.PreserveQuery = p.FromValue.PreserveQueryString != nil && *p.FromValue.PreserveQueryString
+	}
+	if rule.StatusCode == 0 {
+		// The API's default when no status code is given.
+		rule.StatusCode = 301
+	}
+	return rule
+}
+
+// PageRule is a legacy page rule.
+type PageRule struct {
+	ID       string           `json:"id"`
+	Pattern  string           `json:"pattern"`
+	Status   string           `json:"status"`
+	Priority int              `json:"priority"`
+	Actions  map[string]any   `json:"actions"`
+	Forward  *PageRuleForward `json:"forwarding_url,omitempty"`
+}
+
+// PageRuleForward is the forwarding_url action of a page rule.
+type PageRuleForward struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+}
+
+// PageRules returns the current zone's page rules, highest priority first.
+func (c *Client) PageRules(ctx context.Context) ([]PageRule, error) {
+	if c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	}
+	prs, err := c.api.ListPageRules(ctx, c.zoneID)
+	if err != nil {
+		return nil, apiError(err, "list page rules", PermPageRulesRead)
+	}
+	rules := make([]PageRule, 0, len(prs))
+	for _, pr := range prs {
+		rules = append(rules, pageRuleFrom(pr))
+	}
+	return rules, nil
+}
+
+func pageRuleFrom(pr cloudflare.PageRule) PageRule {
+	rule := PageRule{ID: pr.ID, Status: pr.Status, Priority: pr.Priority, Actions: map[string]any{}}
+	for _, t := range pr.Targets {
+		if t.Target == "url" {
+			rule.Pattern = t.Constraint.Value
+		}
+	}
+	for _, a := range pr.Actions {
+		rule.Actions[a.ID] = a.Value
+		if a.ID != "forwarding_url" {
+			continue
+		}
+		if v, ok := a.Value.(map[string]any); ok {
+			fwd := &PageRuleForward{}
+			fwd.URL, _ = v["url"].(string)
+			if code, ok := v["status_code"].(float64); ok {
+				fwd.StatusCode = int(code)
+			}
+			rule.Forward = fwd
+		}
+	}
+	return rule
+}
@@ -0,0 +1,47 @@
+package cloudflare
+
+import (
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func TestRedirectRuleRoundTrip(t *testing.T) {
+	want := RedirectRule{
+		Description:      "docs",
+		Expression:       `(http.host eq "example.com")`,
+		Enabled:          true,
+		TargetExpression: `concat("https://example.org", http.request.uri.path)`,
+		StatusCode:       308,
+		PreserveQuery:    true,
+	}
+	if got := redirectRuleFrom(want.rulesetRule()); got != want {
+		t.Errorf("round trip: got %+v, want %+v", got, want)
+	}
+
+	if got := redirectRuleFrom(cloudflare.RulesetRule{Action: "redirect"}); got.StatusCode != 301 || !got.Enabled {
+		t.Errorf("defaults: got %+v", got)
+	}
+}
+
+func TestPageRuleFrom(t *testing.T) {
+	pr := cloudflare.PageRule{
+		ID: "abc",
+		Actions: []cloudflare.PageRuleAction{
+			{ID: "forwarding_url", Value: map[string]any{"url": "https://example.org/$1", "status_code": float64(302)}},
+		},
+		Status: "active",
+	}
+	pr.Targets = append(pr.Targets, cloudflare.PageRuleTarget{Target: "url"})
+	pr.Targets[0].Constraint.Value = "example.com/*"
+
+	got := pageRuleFrom(pr)
+	if got.Pattern != "example.com/*" || got.Forward == nil || got.Forward.URL != "https://example.org/$1" || got.Forward.StatusCode != 302 {
+		t.Errorf("got %+v (forward %+v)", got, got.Forward)
+	}
+
+	pr.Actions = []cloudflare.PageRuleAction{{ID: "cache_level", Value: "bypass"}}
+	if got := pageRuleFrom(pr); got.Forward != nil || got.Actions["cache_level"] != "bypass" {
+		t.Errorf("non-forwarding rule: got %+v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// Permission group names for zone settings.
+const (
+	PermZoneSettingsRead  = "Zone Settings Read"
+	PermZoneSettingsWrite = "Zone Settings Write"
+)
+
+// ZoneSettings returns all settings of the current zone.
+func (c *Client) ZoneSettings(ctx context.Context) ([]cloudflare.ZoneSetting, error) {
+	if c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	}
+	res, err := c.api.ZoneSettings(ctx, c.zoneID)
+	if err != nil {
+		return nil, apiError(err, "get zone settings", PermZoneSettingsRead)
+	}
+	return res.Result, nil
+}
+
+// ZoneSettingValues returns the current zone's settings as a map from
+// setting ID to value.
+func (c *Client) ZoneSettingValues(ctx context.Context) (map[string]any, error) {
+	settings, err := c.ZoneSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]any, len(settings))
+	for _, s := range settings {
+		values[s.ID] = s.Value
+	}
+	return values, nil
+}
+
+// UpdateZoneSetting sets one setting of the current zone.
+func (c *Client) UpdateZoneSetting(ctx context.Context, id string, value any) (*cloudflare.ZoneSetting, error) {
+	if c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	}
+	setting, err := c.api.UpdateZoneSetting(ctx, cloudflare.ZoneIdentifier(c.zoneID), cloudflare.UpdateZoneSettingParams{Name: id, Value: value})
+	if err != nil {
+		return nil, apiError(err, "update setting "+id, PermZoneSettingsWrite)
+	}
+	return &setting, nil
+}
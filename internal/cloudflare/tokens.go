@@ -0,0 +1,140 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// permissionAliases maps the short names accepted on the command line to
+// Cloudflare permission group names.
+var permissionAliases = map[string]string{
+	"zone:read":       "Zone Read",
+	"zone:edit":       "Zone Write",
+	"dns:read":        "DNS Read",
+	"dns:edit":        "DNS Write",
+	"cache:purge":     "Cache Purge",
+	"settings:read":   "Zone Settings Read",
+	"settings:edit":   "Zone Settings Write",
+	"ssl:read":        "SSL and Certificates Read",
+	"ssl:edit":        "SSL and Certificates Write",
+	"firewall:read":   "Firewall Services Read",
+	"firewall:edit":   "Firewall Services Write",
+	"page-rules:read": "Page Rules Read",
+	"page-rules:edit": "Page Rules Write",
+}
+
+// PermissionAliases returns the supported short permission names, sorted.
+func PermissionAliases() []string {
+	names := make([]string, 0, len(permissionAliases))
+	for name := range permissionAliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TokenRequest describes a zone-scoped API token to create.
+type TokenRequest struct {
+	Name        string
+	Zones       []string
+	Permissions []string
+	TTL         time.Duration
+	IPAllow     []string
+}
+
+func (c *Client) ListTokens(ctx context.Context) ([]cloudflare.APIToken, error) {
+	tokens, err := c.api.APITokens(ctx)
+	if err != nil {
+		return nil, apiError(err, "list API tokens", PermAPITokensRead)
+	}
+	return tokens, nil
+}
+
+// CreateToken mints a token limited to the requested zones and permission
+// groups. Permissions may be short aliases such as "dns:edit" or full
+// permission group names. The returned token's Value is only available
+// from this call.
+func (c *Client) CreateToken(ctx context.Context, req TokenRequest) (*cloudflare.APIToken, error) {
+	if len(req.Zones) == 0 {
+		return nil, fmt.Errorf("at least one zone is required")
+	}
+	if len(req.Permissions) == 0 {
+		return nil, fmt.Errorf("at least one permission is required")
+	}
+
+	groups, err := c.api.ListAPITokensPermissionGroups(ctx)
+	if err != nil {
+		return nil, apiError(err, "list permission groups", PermAPITokensRead)
+	}
+	byName := make(map[string]cloudflare.APITokenPermissionGroups, len(groups))
+	for _, g := range groups {
+		byName[strings.ToLower(g.Name)] = g
+	}
+
+	var selected []cloudflare.APITokenPermissionGroups
+	for _, perm := range req.Permissions {
+		name := perm
+		if alias, ok := permissionAliases[strings.ToLower(perm)]; ok {
+			name = alias
+		}
+		g, ok := byName[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown permission %q", perm)
+		}
+		selected = append(selected, cloudflare.APITokenPermissionGroups{ID: g.ID})
+	}
+
+	resources := make(map[string]interface{}, len(req.Zones))
+	for _, zone := range req.Zones {
+		zoneID, err := c.api.ZoneIDByName(zone)
+		if err != nil {
+			return nil, apiError(err, "find zone "+zone, PermZoneRead)
+		}
+		resources["com.cloudflare.api.account.zone."+zoneID] = "*"
+	}
+
+	token := cloudflare.APIToken{
+		Name: req.Name,
+		Policies: []cloudflare.APITokenPolicies{{
+			Effect:           "allow",
+			Resources:        resources,
+			PermissionGroups: selected,
+		}},
+	}
+	if req.TTL > 0 {
+		expires := time.Now().Add(req.TTL).UTC().Truncate(time.Second)
+		token.ExpiresOn = &expires
+	}
+	if len(req.IPAllow) > 0 {
+		token.Condition = &cloudflare.APITokenCondition{
+			RequestIP: &cloudflare.APITokenRequestIPCondition{In: req.IPAllow},
+		}
+	}
+
+	created, err := c.api.CreateAPIToken(ctx, token)
+	if err != nil {
+		return nil, apiError(err, "create API token", PermAPITokensWrite)
+	}
+	return &created, nil
+}
+
+// RollToken replaces the token's secret and returns the new value.
+func (c *Client) RollToken(ctx context.Context, tokenID string) (string, error) {
+	value, err := c.api.RollAPIToken(ctx, tokenID)
+	if err != nil {
+		return "", apiError(err, "roll API token", PermAPITokensWrite)
+	}
+	return value, nil
+}
+
+func (c *Client) DeleteToken(ctx context.Context, tokenID string) error {
+	if err := c.api.DeleteAPIToken(ctx, tokenID); err != nil {
+		return apiError(err, "revoke API token", PermAPITokensWrite)
+	}
+	return nil
+}
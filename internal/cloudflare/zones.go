@@ -0,0 +1,263 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+// NormalizeName returns the fully-qualified, lower-case form of a record
+// name without the trailing dot. "@" and "" mean the zone apex, names that
+// end in a dot or already end in the zone are taken as fully qualified, and
+// anything else is relative to zone.
+func NormalizeName(name, zone string) string {
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	switch {
+	case name == "@" || name == "":
+		return zone
+	case strings.HasSuffix(name, "."):
+		return strings.TrimSuffix(name, ".")
+	case name == zone || strings.HasSuffix(name, "."+zone):
+		return name
+	}
+	return name + "." + zone
+}
+
+// InZone reports whether the fully-qualified name lies within zone.
+func InZone(name, zone string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+	return name == zone || strings.HasSuffix(name, "."+zone)
+}
+
+// MatchZone returns the zone name from zones that is the longest suffix of
+// name, so that "a.b.example.co.uk" picks "b.example.co.uk" over
+// "example.co.uk". Matching ignores case and trailing dots.
+func MatchZone(zones []string, name string) (string, bool) {
+	best := ""
+	for _, zone := range zones {
+		if InZone(name, zone) && len(strings.TrimSuffix(zone, ".")) > len(strings.TrimSuffix(best, ".")) {
+			best = zone
+		}
+	}
+	return best, best != ""
+}
+
+// SetZoneForName selects the zone that contains the fully-qualified name,
+// using the longest matching zone suffix among the account's zones.
+func (c *Client) SetZoneForName(ctx context.Context, name string) (*cloudflare.Zone, error) {
+	zones, err := c.ListZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(zones))
+	for i, z := range zones {
+		names[i] = z.Name
+	}
+	match, ok := MatchZone(names, name)
+	if !ok {
+		return nil, fmt.Errorf("no zone in this account contains %s", name)
+	}
+
+	for i := range zones {
+		if zones[i].Name == match {
+			c.zoneID = zones[i].ID
+			c.zoneName = strings.ToLower(zones[i].Name)
+			return &zones[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no zone in this account contains %s", name)
+}
+
+// Zone returns the details of the zone selected with SetZone or
+// SetZoneForName.
+func (c *Client) Zone(ctx context.Context) (*cloudflare.Zone, error) {
+	if c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	}
+	zone, err := c.api.ZoneDetails(ctx, c.zoneID)
+	if err != nil {
+		return nil, apiError(err, "get zone details", PermZoneRead)
+	}
+	return &zone, nil
+}
+
+// ZoneInfo is the part of a zone's details cfcli shows.
+type ZoneInfo struct {
+	ID                  string    `json:"id"`
+	Name                string    `json:"name"`
+	Status              string    `json:"status"`
+	Paused              bool      `json:"paused"`
+	Type                string    `json:"type"`
+	Plan                string    `json:"plan"`
+	Account             string    `json:"account,omitempty"`
+	AccountID           string    `json:"account_id,omitempty"`
+	NameServers         []string  `json:"name_servers"`
+	OriginalNameServers []string  `json:"original_name_servers,omitempty"`
+	OriginalRegistrar   string    `json:"original_registrar,omitempty"`
+	OriginalDNSHost     string    `json:"original_dns_host,omitempty"`
+	VerificationKey     string    `json:"verification_key,omitempty"`
+	CreatedOn           time.Time `json:"created_on"`
+	ModifiedOn          time.Time `json:"modified_on"`
+
+	// Records is the number of DNS records, when it was counted.
+	Records *int `json:"records,omitempty"`
+}
+
+// NewZoneInfo returns the details of z shown by cfcli.
+func NewZoneInfo(z *cloudflare.Zone) ZoneInfo {
+	return ZoneInfo{
+		ID:                  z.ID,
+		Name:                z.Name,
+		Status:              z.Status,
+		Paused:              z.Paused,
+		Type:                z.Type,
+		Plan:                z.Plan.Name,
+		Account:             z.Account.Name,
+		AccountID:           z.Account.ID,
+		NameServers:         z.NameServers,
+		OriginalNameServers: z.OriginalNS,
+		OriginalRegistrar:   z.OriginalRegistrar,
+		OriginalDNSHost:     z.OriginalDNSHost,
+		VerificationKey:     z.VerificationKey,
+		CreatedOn:           z.CreatedOn,
+		ModifiedOn:          z.ModifiedOn,
+	}
+}
+
+// CountDNSRecords returns the number of DNS records in the zone with the
+// given ID, without fetching them.
+func (c *Client) CountDNSRecords(ctx context.Context, zoneID string) (int, error) {
+	params := cloudflare.ListDNSRecordsParams{ResultInfo: cloudflare.ResultInfo{PerPage: 1}}
+	_, info, err := c.api.ListDNSRecords(ctx, cloudflare.ZoneIdentifier(zoneID), params)
+	if err != nil {
+		return 0, apiError(err, "count DNS records", PermDNSRead)
+	}
+	return info.Total, nil
+}
+
+// FilterZones returns the zones with the given status whose account has
+// the given ID or name. Empty arguments match any zone.
+func FilterZones(zones []cloudflare.Zone, status, account string) []cloudflare.Zone {
+	var filtered []cloudflare.Zone
+	for _, z := range zones {
+		if status != "" && !strings.EqualFold(z.Status, status) {
+			continue
+		}
+		if account != "" && z.Account.ID != account && !strings.EqualFold(z.Account.Name, account) {
+			continue
+		}
+		filtered = append(filtered, z)
+	}
+	return filtered
+}
+
+// CreateZone adds domain to the account with the given ID, or to the
+// token's only account when accountID is empty. zoneType is "full" or
+// "partial"; jumpStart imports the domain's existing DNS records.
+func (c *Client) CreateZone(ctx context.Context, domain, accountID string, jumpStart bool, zoneType string) (*cloudflare.Zone, error) {
+	accountID, err := c.ResolveAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	zone, err := c.api.CreateZone(ctx, strings.ToLower(strings.TrimSuffix(domain, ".")), jumpStart, cloudflare.Account{ID: accountID}, zoneType)
+	if err != nil {
+		return nil, apiError(err, "create zone", PermZoneWrite)
+	}
+	c.InvalidateZones()
+	c.zoneID = zone.ID
+	c.zoneName = strings.ToLower(zone.Name)
+	return &zone, nil
+}
+
+// ResolveAccountID returns accountID, or when it is empty the ID of the
+// token's only account.
+func (c *Client) ResolveAccountID(ctx context.Context, accountID string) (string, error) {
+	if accountID != "" {
+		return accountID, nil
+	}
+	accounts, _, err := c.api.Accounts(ctx, cloudflare.AccountsListParams{})
+	if err != nil {
+		return "", apiError(err, "list accounts", "")
+	}
+	switch len(accounts) {
+	case 0:
+		return "", fmt.Errorf("the API token has access to no accounts")
+	case 1:
+		return accounts[0].ID, nil
+	}
+	names := make([]string, len(accounts))
+	for i, a := range accounts {
+		names[i] = fmt.Sprintf("%s (%s)", a.Name, a.ID)
+	}
+	return "", fmt.Errorf("the API token has access to several accounts, choose one with --account-id: %s", strings.Join(names, ", "))
+}
+
+// DeleteZone deletes the current zone and all of its records.
+func (c *Client) DeleteZone(ctx context.Context) error {
+	if c.zoneID == "" {
+		return fmt.Errorf("zone not set")
+	}
+	if _, err := c.api.DeleteZone(ctx, c.zoneID); err != nil {
+		return apiError(err, "delete zone", PermZoneWrite)
+	}
+	c.InvalidateZones()
+	c.zoneID, c.zoneName = "", ""
+	return nil
+}
+
+// SetPaused pauses or unpauses the current zone. A paused zone serves DNS
+// only, without Cloudflare's proxy, caching or security features.
+func (c *Client) SetPaused(ctx context.Context, paused bool) (*cloudflare.Zone, error) {
+	if c.zoneID == "" {
+		return nil, fmt.Errorf("zone not set")
+	}
+	zone, err := c.api.ZoneSetPaused(ctx, c.zoneID, paused)
+	if err != nil {
+		return nil, apiError(err, "update zone", PermZoneWrite)
+	}
+	c.InvalidateZones()
+	return &zone, nil
+}
+
+// ActivationCheck asks Cloudflare to check a pending zone's nameservers
+// again. The API allows this only once every few minutes.
+func (c *Client) ActivationCheck(ctx context.Context) error {
+	if c.zoneID == "" {
+		return fmt.Errorf("zone not set")
+	}
+	if _, err := c.api.ZoneActivationCheck(ctx, c.zoneID); err != nil {
+		return apiError(err, "request activation check", PermZoneWrite)
+	}
+	c.InvalidateZones()
+	return nil
+}
+
+// SameNameservers reports whether two nameserver lists hold the same
+// hosts, ignoring order, case and trailing dots.
+func SameNameservers(a, b []string) bool {
+	set := func(list []string) map[string]bool {
+		m := make(map[string]bool, len(list))
+		for _, ns := range list {
+			m[strings.ToLower(strings.TrimSuffix(ns, "."))] = true
+		}
+		return m
+	}
+	x, y := set(a), set(b)
+	if len(x) != len(y) || len(x) == 0 {
+		return false
+	}
+	for ns := range x {
+		if !y[ns] {
+			return false
+		}
+	}
+	return true
+}
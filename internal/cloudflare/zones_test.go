@@ -0,0 +1,96 @@
+package cloudflare
+
+import (
+	"testing"
+
+	cloudflare "github.com/cloudflare/cloudflare-go"
+)
+
+func TestMatchZone(t *testing.T) {
+	zones := []string{"example.com", "staging.example.com", "example.co.uk", "ample.com"}
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"example.com", "example.com", true},
+		{"www.example.com", "example.com", true},
+		{"api.staging.example.com.", "staging.example.com", true},
+		{"WWW.Example.CO.UK", "example.co.uk", true},
+		{"notexample.com", "", false},
+		{"example.org", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := MatchZone(zones, tt.name)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("MatchZone(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name, zone, want string
+	}{
+		{"@", "example.com", "example.com"},
+		{"", "example.com.", "example.com"},
+		{"www", "example.com", "www.example.com"},
+		{"WWW", "Example.com", "www.example.com"},
+		{"www.example.com", "example.com", "www.example.com"},
+		{"www.example.com.", "example.com", "www.example.com"},
+		{"other.org.", "example.com", "other.org"},
+		{"a.b", "example.com", "a.b.example.com"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeName(tt.name, tt.zone); got != tt.want {
+			t.Errorf("NormalizeName(%q, %q) = %q, want %q", tt.name, tt.zone, got, tt.want)
+		}
+	}
+}
+
+func TestSameNameservers(t *testing.T) {
+	assigned := []string{"ada.ns.cloudflare.com", "bob.ns.cloudflare.com"}
+	tests := []struct {
+		current []string
+		want    bool
+	}{
+		{[]string{"BOB.ns.cloudflare.com.", "ada.ns.cloudflare.com"}, true},
+		{[]string{"ada.ns.cloudflare.com"}, false},
+		{[]string{"ns1.registrar.example", "ns2.registrar.example"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := SameNameservers(assigned, tt.current); got != tt.want {
+			t.Errorf("SameNameservers(%v) = %v, want %v", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestFilterZones(t *testing.T) {
+	zones := []cloudflare.Zone{
+		{Name: "example.com", Status: "active", Account: cloudflare.Account{ID: "a1", Name: "Personal"}},
+		{Name: "example.org", Status: "pending", Account: cloudflare.Account{ID: "a1", Name: "Personal"}},
+		{Name: "example.net", Status: "active", Account: cloudflare.Account{ID: "b2", Name: "Work"}},
+	}
+	tests := []struct {
+		status, account string
+		want            int
+	}{
+		{"", "", 3},
+		{"active", "", 2},
+		{"Pending", "", 1},
+		{"", "a1", 2},
+		{"", "work", 1},
+		{"active", "personal", 1},
+		{"moved", "", 0},
+	}
+	for _, tt := range tests {
+		if got := FilterZones(zones, tt.status, tt.account); len(got) != tt.want {
+			t.Errorf("FilterZones(%q, %q) returned %d zones, want %d", tt.status, tt.account, len(got), tt.want)
+		}
+	}
+}
@@ -1,98 +1,353 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
+// Source identifies where an effective setting came from.
+type Source string
+
+const (
+	SourceUnset    Source = "unset"
+	SourceFlag     Source = "flag"
+	SourceEnv      Source = "env"
+	SourceAccount  Source = "account"
+	SourceDefaults Source = "defaults"
+)
+
+// Origin records the source of a setting together with the specific flag,
+// environment variable or account name that provided it.
+type Origin struct {
+	Source Source
+	Name   string
+}
+
+func (o Origin) String() string {
+	if o.Source == "" {
+		return string(SourceUnset)
+	}
+	if o.Name == "" {
+		return string(o.Source)
+	}
+	return string(o.Source) + " (" + o.Name + ")"
+}
+
 type Config struct {
 	Token   string
 	Email   string
 	Domain  string
 	Account string
+
+	// Origins maps a field name (token, email, domain, account) to the
+	// source its value was taken from.
+	Origins map[string]Origin
+
+	// Candidates holds, per field, every source that offered a value, in
+	// precedence order. The first entry is the one in effect, except for an
+	// email that does not belong with the token.
+	Candidates map[string][]Candidate
+
+	// DomainAlias is the account alias Domain was given as, if any.
+	DomainAlias string
+
+	// TTL and Proxied are the record defaults configured for Domain in the
+	// account's domains list. They are zero when none are configured.
+	TTL     int
+	Proxied *bool
+}
+
+// Fields lists the settings tracked in Config.Origins, in display order.
+var Fields = []string{"token", "email", "domain", "account"}
+
+// Get returns the value of the named field.
+func (c *Config) Get(field string) string {
+	switch field {
+	case "token":
+		return c.Token
+	case "email":
+		return c.Email
+	case "domain":
+		return c.Domain
+	case "account":
+		return c.Account
+	}
+	return ""
+}
+
+// Set assigns the named field and records where the value came from.
+func (c *Config) Set(field, value string, origin Origin) {
+	switch field {
+	case "token":
+		c.Token = value
+	case "email":
+		c.Email = value
+	case "domain":
+		c.Domain = value
+	case "account":
+		c.Account = value
+	default:
+		return
+	}
+	if c.Origins == nil {
+		c.Origins = make(map[string]Origin)
+	}
+	c.Origins[field] = origin
+}
+
+// Origin returns where the named field was taken from.
+func (c *Config) Origin(field string) Origin {
+	if o, ok := c.Origins[field]; ok {
+		return o
+	}
+	return Origin{Source: SourceUnset}
 }
 
 type AccountConfig struct {
-	Token  string `mapstructure:"token"`
-	Email  string `mapstructure:"email"`
-	Domain string `mapstructure:"domain"`
+	Token string `mapstructure:"token" yaml:"token,omitempty"`
+	Email string `mapstructure:"email" yaml:"email,omitempty"`
+	// Domain is the account's default domain. It may be a zone name or one
+	// of the aliases in Domains.
+	Domain  string                  `mapstructure:"domain" yaml:"domain,omitempty"`
+	Domains map[string]DomainConfig `mapstructure:"domains" yaml:"domains,omitempty"`
+}
+
+// DomainConfig is a zone managed by an account, keyed by alias. In YAML it
+// can be written as just the zone name ("prod: example.com") or as a map
+// with per-domain record defaults.
+type DomainConfig struct {
+	Name    string `mapstructure:"name" yaml:"name"`
+	TTL     int    `mapstructure:"ttl" yaml:"ttl,omitempty"`
+	Proxied *bool  `mapstructure:"proxied" yaml:"proxied,omitempty"`
+}
+
+// MarshalYAML writes domains without record defaults in the short form.
+func (d DomainConfig) MarshalYAML() (interface{}, error) {
+	if d.TTL == 0 && d.Proxied == nil {
+		return d.Name, nil
+	}
+	type plain DomainConfig
+	return plain(d), nil
+}
+
+// domainConfigHook lets a plain string decode into a DomainConfig.
+func domainConfigHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if to == reflect.TypeOf(DomainConfig{}) && from.Kind() == reflect.String {
+		return DomainConfig{Name: data.(string)}, nil
+	}
+	return data, nil
+}
+
+// unmarshal decodes the config read by v into cf.
+func unmarshal(v *viper.Viper, cf *ConfigFile) error {
+	return v.Unmarshal(cf, viper.DecodeHook(domainConfigHook))
+}
+
+type DefaultsConfig struct {
+	Token   string `mapstructure:"token" yaml:"token,omitempty"`
+	Email   string `mapstructure:"email" yaml:"email,omitempty"`
+	Domain  string `mapstructure:"domain" yaml:"domain,omitempty"`
+	Account string `mapstructure:"account" yaml:"account,omitempty"`
 }
 
 type ConfigFile struct {
-	Defaults struct {
-		Token   string `mapstructure:"token"`
-		Email   string `mapstructure:"email"`
-		Domain  string `mapstructure:"domain"`
-		Account string `mapstructure:"account"`
-	} `mapstructure:"defaults"`
-	Accounts map[string]AccountConfig `mapstructure:"accounts"`
+	Defaults DefaultsConfig           `mapstructure:"defaults" yaml:"defaults,omitempty"`
+	Accounts map[string]AccountConfig `mapstructure:"accounts" yaml:"accounts,omitempty"`
 }
 
-func LoadConfig(configPath, accountName string) (*Config, error) {
-	v := viper.New()
+// Candidate is a value offered for a field by one configuration source.
+type Candidate struct {
+	Field  string
+	Value  string
+	Origin Origin
+}
 
-	// Set config file path
-	if configPath != "" {
-		v.SetConfigFile(configPath)
-	} else {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, err
+// TokenEnvVars lists the environment variables consulted for the API token,
+// in precedence order. CF_API_KEY is kept for compatibility with the
+// original cloudflare-cli.
+var TokenEnvVars = []string{"CF_API_TOKEN", "CLOUDFLARE_API_TOKEN", "CF_API_KEY"}
+
+// LoadConfig resolves the effective configuration field by field. For each
+// field the first source that provides a non-empty value wins, in this
+// order:
+//
+//  1. command-line flags
+//  2. the account selected with --account
+//  3. environment variables
+//  4. the account named in defaults.account
+//  5. the top-level defaults
+//
+// The email is not resolved on its own: it switches the client to legacy
+// global API key auth, so it is only taken from the source that provided
+// the token, or from --email or CF_API_EMAIL.
+//
+// flags maps field names to the values given on the command line. A config
+// file that cannot be read is reported as an error, but the returned Config
+// is still resolved from flags and the environment.
+func LoadConfig(configPath, accountName string, flags map[string]string) (*Config, error) {
+	var candidates []Candidate
+	for _, field := range Fields {
+		if field == "account" {
+			continue
+		}
+		candidates = append(candidates, Candidate{field, flags[field], Origin{SourceFlag, "--" + field}})
+	}
+
+	var cf ConfigFile
+	fileErr := readInto(configPath, &cf)
+
+	if accountName != "" {
+		acc, ok := cf.Accounts[accountName]
+		if !ok && fileErr == nil {
+			fileErr = fmt.Errorf("account %q not found in config file", accountName)
 		}
-		configDir := filepath.Join(home, ".config", "cfcli")
-		v.AddConfigPath(configDir)
-		v.SetConfigName("config")
-		v.SetConfigType("yaml")
+		origin := Origin{SourceAccount, accountName}
+		candidates = append(candidates, Candidate{"account", accountName, Origin{SourceFlag, "--account"}})
+		candidates = append(candidates, accountCandidates(acc, origin)...)
 	}
 
-	// Read config file (optional)
-	var cfg ConfigFile
-	if err := v.ReadInConfig(); err == nil {
-		if err := v.Unmarshal(&cfg); err != nil {
-			return nil, err
+	candidates = append(candidates, envCandidates()...)
+
+	if name := cf.Defaults.Account; name != "" {
+		if acc, ok := cf.Accounts[name]; ok {
+			origin := Origin{SourceAccount, name}
+			candidates = append(candidates, Candidate{"account", name, Origin{SourceDefaults, "defaults.account"}})
+			candidates = append(candidates, accountCandidates(acc, origin)...)
+		} else if fileErr == nil {
+			fileErr = fmt.Errorf("defaults.account refers to unknown account %q", name)
+		}
+	}
+
+	origin := Origin{Source: SourceDefaults}
+	candidates = append(candidates,
+		Candidate{"token", cf.Defaults.Token, origin},
+		Candidate{"email", cf.Defaults.Email, origin},
+		Candidate{"domain", cf.Defaults.Domain, origin},
+	)
+
+	config := Resolve(candidates)
+	config.applyDomain(&cf)
+	return config, fileErr
+}
+
+// applyDomain resolves Domain through the selected account's domain aliases
+// and picks up that domain's record defaults.
+func (c *Config) applyDomain(cf *ConfigFile) {
+	acc, ok := cf.Accounts[c.Account]
+	if !ok || c.Domain == "" {
+		return
+	}
+	if d, ok := acc.Domains[strings.ToLower(c.Domain)]; ok && d.Name != "" {
+		c.DomainAlias = c.Domain
+		c.Domain = d.Name
+		c.TTL, c.Proxied = d.TTL, d.Proxied
+		return
+	}
+	for _, d := range acc.Domains {
+		if strings.EqualFold(d.Name, c.Domain) {
+			c.TTL, c.Proxied = d.TTL, d.Proxied
+			return
 		}
 	}
+}
 
-	// Build final config with precedence: CLI flags > Env vars > Config file
-	config := &Config{}
-
-	// Check environment variables first
-	if token := os.Getenv("CF_API_KEY"); token != "" {
-		config.Token = token
-	}
-	if email := os.Getenv("CF_API_EMAIL"); email != "" {
-		config.Email = email
-	}
-	if domain := os.Getenv("CF_API_DOMAIN"); domain != "" {
-		config.Domain = domain
-	}
-
-	// If no env vars, use config file
-	if config.Token == "" {
-		if accountName != "" {
-			if acc, ok := cfg.Accounts[accountName]; ok {
-				config.Token = acc.Token
-				config.Email = acc.Email
-				config.Domain = acc.Domain
-			}
-		} else if cfg.Defaults.Account != "" {
-			// Use default account
-			if acc, ok := cfg.Accounts[cfg.Defaults.Account]; ok {
-				config.Token = acc.Token
-				config.Email = acc.Email
-				config.Domain = acc.Domain
-			}
-		} else {
-			// Use defaults directly
-			config.Token = cfg.Defaults.Token
-			config.Email = cfg.Defaults.Email
-			config.Domain = cfg.Defaults.Domain
+// Resolve builds a Config from candidates given in precedence order. Empty
+// candidates are ignored. Every non-empty candidate is kept in
+// Config.Candidates so callers can explain which sources were overridden.
+// An email is only used if it was given explicitly or comes from the same
+// source as the token, so a token is never paired with another source's
+// email and sent as a global API key.
+func Resolve(candidates []Candidate) *Config {
+	config := &Config{
+		Origins:    make(map[string]Origin),
+		Candidates: make(map[string][]Candidate),
+	}
+	for _, c := range candidates {
+		if c.Value == "" {
+			continue
+		}
+		config.Candidates[c.Field] = append(config.Candidates[c.Field], c)
+		if _, set := config.Origins[c.Field]; !set && c.Field != "email" {
+			config.Set(c.Field, c.Value, c.Origin)
+		}
+	}
+	for _, c := range config.Candidates["email"] {
+		explicit := c.Origin.Source == SourceFlag || c.Origin.Source == SourceEnv
+		if explicit || c.Origin == config.Origin("token") {
+			config.Set(c.Field, c.Value, c.Origin)
+			break
 		}
 	}
+	return config
+}
 
-	return config, nil
+func envCandidates() []Candidate {
+	var candidates []Candidate
+	for _, name := range TokenEnvVars {
+		candidates = append(candidates, Candidate{"token", os.Getenv(name), Origin{SourceEnv, name}})
+	}
+	candidates = append(candidates,
+		Candidate{"email", os.Getenv("CF_API_EMAIL"), Origin{SourceEnv, "CF_API_EMAIL"}},
+		Candidate{"domain", os.Getenv("CF_API_DOMAIN"), Origin{SourceEnv, "CF_API_DOMAIN"}},
+	)
+	return candidates
+}
+
+func accountCandidates(acc AccountConfig, origin Origin) []Candidate {
+	return []Candidate{
+		{"token", acc.Token, origin},
+		{"email", acc.Email, origin},
+		{"domain", acc.Domain, origin},
+	}
+}
+
+// readInto reads the config file into cf. A missing config file is not an
+// error, whether it is the default one or given with --config, so that
+// commands such as "config init" can create it.
+func readInto(configPath string, cf *ConfigFile) error {
+	if configPath != "" {
+		if _, err := os.Stat(configPath); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+	}
+	v, err := newViper(configPath)
+	if err != nil {
+		return err
+	}
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return err
+	}
+	return unmarshal(v, cf)
+}
+
+func newViper(configPath string) (*viper.Viper, error) {
+	v := viper.New()
+
+	// Set config file path
+	if configPath != "" {
+		v.SetConfigFile(configPath)
+		return v, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	configDir := filepath.Join(home, ".config", "cfcli")
+	v.AddConfigPath(configDir)
+	v.SetConfigName("config")
+	v.SetConfigType("yaml")
+	return v, nil
 }
 
 func GetDefaultConfigPath() string {
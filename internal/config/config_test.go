@@ -0,0 +1,247 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testConfig = `defaults:
+    token: defaults-token
+    domain: defaults.com
+    account: personal
+accounts:
+    work:
+        token: work-token
+        domain: work.com
+    personal:
+        token: personal-token
+        email: me@example.com
+        domain: personal.com
+`
+
+func writeTestConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range append(TokenEnvVars, "CF_API_EMAIL", "CF_API_DOMAIN") {
+		t.Setenv(name, "")
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		account    string
+		flags      map[string]string
+		env        map[string]string
+		wantToken  string
+		wantDomain string
+		wantOrigin Origin
+	}{
+		{
+			name:       "default account",
+			wantToken:  "personal-token",
+			wantDomain: "personal.com",
+			wantOrigin: Origin{SourceAccount, "personal"},
+		},
+		{
+			name:       "env overrides default account",
+			env:        map[string]string{"CF_API_KEY": "env-token"},
+			wantToken:  "env-token",
+			wantDomain: "personal.com",
+			wantOrigin: Origin{SourceEnv, "CF_API_KEY"},
+		},
+		{
+			name:       "explicit account overrides env",
+			account:    "work",
+			env:        map[string]string{"CF_API_TOKEN": "env-token", "CF_API_DOMAIN": "env.com"},
+			wantToken:  "work-token",
+			wantDomain: "work.com",
+			wantOrigin: Origin{SourceAccount, "work"},
+		},
+		{
+			name:       "modern token variable wins over CF_API_KEY",
+			env:        map[string]string{"CF_API_KEY": "old", "CLOUDFLARE_API_TOKEN": "new"},
+			wantToken:  "new",
+			wantDomain: "personal.com",
+			wantOrigin: Origin{SourceEnv, "CLOUDFLARE_API_TOKEN"},
+		},
+		{
+			name:       "flags override everything",
+			account:    "work",
+			flags:      map[string]string{"token": "flag-token"},
+			env:        map[string]string{"CF_API_TOKEN": "env-token"},
+			wantToken:  "flag-token",
+			wantDomain: "work.com",
+			wantOrigin: Origin{SourceFlag, "--token"},
+		},
+	}
+
+	path := writeTestConfig(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := LoadConfig(path, tt.account, tt.flags)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.Token != tt.wantToken {
+				t.Errorf("Token = %q, want %q", cfg.Token, tt.wantToken)
+			}
+			if cfg.Domain != tt.wantDomain {
+				t.Errorf("Domain = %q, want %q", cfg.Domain, tt.wantDomain)
+			}
+			if got := cfg.Origin("token"); got != tt.wantOrigin {
+				t.Errorf("Origin(token) = %v, want %v", got, tt.wantOrigin)
+			}
+		})
+	}
+}
+
+func TestLoadConfigEmailFollowsToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		account   string
+		flags     map[string]string
+		env       map[string]string
+		wantEmail string
+	}{
+		{
+			name:      "account token keeps its email",
+			wantEmail: "me@example.com",
+		},
+		{
+			name: "env token drops default account email",
+			env:  map[string]string{"CF_API_TOKEN": "env-token"},
+		},
+		{
+			name:    "flag token drops selected account email",
+			account: "personal",
+			flags:   map[string]string{"token": "flag-token"},
+		},
+		{
+			name:      "explicit env email is kept",
+			env:       map[string]string{"CF_API_TOKEN": "env-token", "CF_API_EMAIL": "env@example.com"},
+			wantEmail: "env@example.com",
+		},
+	}
+
+	path := writeTestConfig(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := LoadConfig(path, tt.account, tt.flags)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.Email != tt.wantEmail {
+				t.Errorf("Email = %q, want %q", cfg.Email, tt.wantEmail)
+			}
+		})
+	}
+}
+
+func TestLoadConfigUnknownAccount(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("CF_API_TOKEN", "env-token")
+
+	cfg, err := LoadConfig(writeTestConfig(t), "missing", nil)
+	if err == nil {
+		t.Fatal("expected error for unknown account")
+	}
+	if cfg.Token != "env-token" {
+		t.Errorf("Token = %q, want env token to still apply", cfg.Token)
+	}
+}
+
+func TestLoadConfigMissingExplicitFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("CF_API_TOKEN", "env-token")
+
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "new.yaml"), "", nil)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v, want nil for a file that does not exist yet", err)
+	}
+	if cfg.Token != "env-token" {
+		t.Errorf("Token = %q, want %q", cfg.Token, "env-token")
+	}
+}
+
+func TestLoadConfigDomainAliases(t *testing.T) {
+	clearEnv(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `defaults:
+    account: work
+accounts:
+    work:
+        token: work-token
+        domain: prod
+        domains:
+            prod:
+                name: example.com
+                ttl: 300
+                proxied: true
+            staging: example.org
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		flags       map[string]string
+		wantDomain  string
+		wantTTL     int
+		wantProxied bool
+	}{
+		{"default alias", nil, "example.com", 300, true},
+		{"alias from flag", map[string]string{"domain": "staging"}, "example.org", 0, false},
+		{"zone name from flag", map[string]string{"domain": "example.com"}, "example.com", 300, true},
+		{"unknown domain", map[string]string{"domain": "other.com"}, "other.com", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfig(path, "", tt.flags)
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.Domain != tt.wantDomain {
+				t.Errorf("Domain = %q, want %q", cfg.Domain, tt.wantDomain)
+			}
+			if cfg.TTL != tt.wantTTL {
+				t.Errorf("TTL = %d, want %d", cfg.TTL, tt.wantTTL)
+			}
+			if got := cfg.Proxied != nil && *cfg.Proxied; got != tt.wantProxied {
+				t.Errorf("Proxied = %v, want %v", got, tt.wantProxied)
+			}
+		})
+	}
+}
+
+func TestSetKeyTTL(t *testing.T) {
+	var cf ConfigFile
+	for _, v := range []string{"1", "60", "86400", "0"} {
+		if err := cf.SetKey("accounts.work.domains.prod.ttl", v); err != nil {
+			t.Errorf("SetKey(ttl, %s): %v", v, err)
+		}
+	}
+	for _, v := range []string{"30", "86401", "-1", "auto"} {
+		if err := cf.SetKey("accounts.work.domains.prod.ttl", v); err == nil {
+			t.Errorf("SetKey(ttl, %s) succeeded, want an error", v)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// ResolvePath returns the config file path to read and write, falling back
+// to the default location when configPath is empty.
+func ResolvePath(configPath string) (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+	path := GetDefaultConfigPath()
+	if path == "" {
+		return "", fmt.Errorf("could not determine home directory for config file")
+	}
+	return path, nil
+}
+
+// ReadConfigFile loads the config file at path. A missing file yields an
+// empty ConfigFile rather than an error so that callers can create it.
+func ReadConfigFile(path string) (*ConfigFile, error) {
+	cf := &ConfigFile{}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return cf, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	if err := unmarshal(v, cf); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return cf, nil
+}
+
+// WriteConfigFile writes cf to path as YAML, creating the parent directory
+// if needed. The file is written with owner-only permissions since it
+// holds API credentials.
+func WriteConfigFile(path string, cf *ConfigFile) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", "":
+	default:
+		return fmt.Errorf("only YAML config files can be written, got %s", path)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(cf); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// ValidateConfigFile checks the config file at path for unknown keys,
+// dangling account references and incomplete accounts. It returns one
+// error per problem found; an empty slice means the file is valid.
+func ValidateConfigFile(path string) ([]error, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var problems []error
+	for _, key := range v.AllKeys() {
+		if !isKnownKey(key) {
+			problems = append(problems, fmt.Errorf("unknown key %q", key))
+		}
+	}
+
+	var cf ConfigFile
+	if err := unmarshal(v, &cf); err != nil {
+		return append(problems, fmt.Errorf("invalid structure: %w", err)), nil
+	}
+	if cf.Defaults.Account != "" {
+		if _, ok := cf.Accounts[cf.Defaults.Account]; !ok {
+			problems = append(problems, fmt.Errorf("defaults.account refers to unknown account %q", cf.Defaults.Account))
+		}
+	}
+	for _, name := range cf.AccountNames() {
+		acc := cf.Accounts[name]
+		if acc.Token == "" {
+			problems = append(problems, fmt.Errorf("account %q has no token", name))
+		}
+		for alias, d := range acc.Domains {
+			if d.Name == "" {
+				problems = append(problems, fmt.Errorf("account %q domain alias %q has no zone name", name, alias))
+			}
+			if d.TTL != 0 && !validTTL(d.TTL) {
+				problems = append(problems, fmt.Errorf("account %q domain %q has invalid ttl %d (1 for auto, or 60-86400)", name, alias, d.TTL))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// validTTL reports whether ttl is a record TTL the API accepts: 1 for
+// automatic, or 60 to 86400 seconds.
+func validTTL(ttl int) bool {
+	return ttl == 1 || (ttl >= 60 && ttl <= 86400)
+}
+
+// AccountNames returns the configured account names in sorted order.
+func (cf *ConfigFile) AccountNames() []string {
+	names := make([]string, 0, len(cf.Accounts))
+	for name := range cf.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetKey assigns a dotted key such as "defaults.domain" or
+// "accounts.work.token" in the config file.
+func (cf *ConfigFile) SetKey(key, value string) error {
+	parts := strings.Split(key, ".")
+	switch {
+	case len(parts) == 2 && parts[0] == "defaults":
+		switch parts[1] {
+		case "token":
+			cf.Defaults.Token = value
+		case "email":
+			cf.Defaults.Email = value
+		case "domain":
+			cf.Defaults.Domain = value
+		case "account":
+			if value != "" {
+				if _, ok := cf.Accounts[value]; !ok {
+					return fmt.Errorf("account %q does not exist", value)
+				}
+			}
+			cf.Defaults.Account = value
+		default:
+			return fmt.Errorf("unknown key %q", key)
+		}
+	case len(parts) == 3 && parts[0] == "accounts":
+		acc := cf.account(parts[1])
+		switch parts[2] {
+		case "token":
+			acc.Token = value
+		case "email":
+			acc.Email = value
+		case "domain":
+			acc.Domain = value
+		default:
+			return fmt.Errorf("unknown key %q", key)
+		}
+		cf.Accounts[parts[1]] = acc
+	case (len(parts) == 4 || len(parts) == 5) && parts[0] == "accounts" && parts[2] == "domains":
+		acc := cf.account(parts[1])
+		if acc.Domains == nil {
+			acc.Domains = make(map[string]DomainConfig)
+		}
+		alias := strings.ToLower(parts[3])
+		d := acc.Domains[alias]
+		field := "name"
+		if len(parts) == 5 {
+			field = parts[4]
+		}
+		switch field {
+		case "name":
+			d.Name = value
+		case "ttl":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid ttl %q: %w", value, err)
+			}
+			if n != 0 && !validTTL(n) {
+				return fmt.Errorf("invalid ttl %d (1 for auto, or 60-86400; 0 to unset)", n)
+			}
+			d.TTL = n
+		case "proxied":
+			if value == "" {
+				d.Proxied = nil
+				break
+			}
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid proxied value %q: %w", value, err)
+			}
+			d.Proxied = &b
+		default:
+			return fmt.Errorf("unknown key %q", key)
+		}
+		acc.Domains[alias] = d
+		cf.Accounts[parts[1]] = acc
+	default:
+		return fmt.Errorf("unknown key %q (expected defaults.<field> or accounts.<name>.<field>)", key)
+	}
+	return nil
+}
+
+// account returns the named account, creating the Accounts map if needed.
+// The caller must store the modified account back.
+func (cf *ConfigFile) account(name string) AccountConfig {
+	if cf.Accounts == nil {
+		cf.Accounts = make(map[string]AccountConfig)
+	}
+	return cf.Accounts[name]
+}
+
+func isKnownKey(key string) bool {
+	parts := strings.Split(key, ".")
+	switch {
+	case len(parts) == 2 && parts[0] == "defaults":
+		switch parts[1] {
+		case "token", "email", "domain", "account":
+			return true
+		}
+	case len(parts) == 3 && parts[0] == "accounts":
+		switch parts[2] {
+		case "token", "email", "domain":
+			return true
+		}
+	case len(parts) == 4 && parts[0] == "accounts" && parts[2] == "domains":
+		return true
+	case len(parts) == 5 && parts[0] == "accounts" && parts[2] == "domains":
+		switch parts[4] {
+		case "name", "ttl", "proxied":
+			return true
+		}
+	}
+	return false
+}
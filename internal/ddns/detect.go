@@ -0,0 +1,197 @@
+// Package ddns keeps A/AAAA records pointed at the host's current public
+// addresses.
+package ddns
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultEndpoints are plain-text IP echo services queried in order until
+// one answers. Each is reached over IPv4 or IPv6 depending on the family
+// being detected.
+var DefaultEndpoints = []string{
+	"https://api64.ipify.org",
+	"https://icanhazip.com",
+	"https://ifconfig.co/ip",
+}
+
+// Family is an IP address family.
+type Family int
+
+const (
+	IPv4 Family = 4
+	IPv6 Family = 6
+)
+
+// RecordType returns the DNS record type holding addresses of this family.
+func (f Family) RecordType() string {
+	if f == IPv6 {
+		return "AAAA"
+	}
+	return "A"
+}
+
+func (f Family) network() string {
+	if f == IPv6 {
+		return "tcp6"
+	}
+	return "tcp4"
+}
+
+func (f Family) matches(ip net.IP) bool {
+	if f == IPv6 {
+		return ip.To4() == nil && ip.To16() != nil
+	}
+	return ip.To4() != nil
+}
+
+// Detector finds the current public address, either from a local network
+// interface or by asking IP echo endpoints.
+type Detector struct {
+	// Interface, when set, is read instead of querying Endpoints.
+	Interface string
+	Endpoints []string
+	Timeout   time.Duration
+
+	mu sync.Mutex
+	// clients holds one HTTP client per family, reused across polls so
+	// that keep-alive connections are too.
+	clients map[Family]*http.Client
+}
+
+// Detect returns the current address of the given family.
+func (d *Detector) Detect(ctx context.Context, family Family) (net.IP, error) {
+	if d.Interface != "" {
+		return interfaceAddr(d.Interface, family)
+	}
+
+	endpoints := d.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = DefaultEndpoints
+	}
+
+	var errs []string
+	for _, endpoint := range endpoints {
+		ip, err := d.query(ctx, endpoint, family)
+		if err == nil {
+			return ip, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", endpoint, err))
+	}
+	return nil, fmt.Errorf("could not detect public IPv%d address: %s", family, strings.Join(errs, "; "))
+}
+
+// client returns the HTTP client that connects over the given family.
+func (d *Detector) client(family Family) *http.Client {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if c := d.clients[family]; c != nil {
+		return c
+	}
+
+	timeout := d.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	c := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, family.network(), addr)
+			},
+			IdleConnTimeout: 90 * time.Second,
+		},
+	}
+	if d.clients == nil {
+		d.clients = make(map[Family]*http.Client)
+	}
+	d.clients[family] = c
+	return c
+}
+
+func (d *Detector) query(ctx context.Context, endpoint string, family Family) (net.IP, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "cfcli-ddns")
+
+	resp, err := d.client(family).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(string(body)))
+	if ip == nil {
+		return nil, fmt.Errorf("response is not an IP address")
+	}
+	if !family.matches(ip) {
+		return nil, fmt.Errorf("got %s, not an IPv%d address", ip, family)
+	}
+	if !isPublic(ip) {
+		return nil, fmt.Errorf("got %s, not a public address", ip)
+	}
+	return ip, nil
+}
+
+func interfaceAddr(name string, family Family) (net.IP, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read interface %s: %w", name, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read addresses of %s: %w", name, err)
+	}
+	var private net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip := ipNet.IP
+		if !family.matches(ip) {
+			continue
+		}
+		if isPublic(ip) {
+			return ip, nil
+		}
+		if ip.IsPrivate() && private == nil {
+			private = ip
+		}
+	}
+	if private != nil {
+		return nil, fmt.Errorf("interface %s has only private IPv%d addresses (such as %s)", name, family, private)
+	}
+	return nil, fmt.Errorf("interface %s has no global IPv%d address", name, family)
+}
+
+// isPublic reports whether ip can be published in public DNS: a global
+// unicast address that is not RFC 1918 or an IPv6 unique local address.
+func isPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	return ip.IsGlobalUnicast()
+}
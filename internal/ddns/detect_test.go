@@ -0,0 +1,57 @@
+package ddns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"203.0.113.7", true},
+		{"2001:db8::7", true},
+		{"192.168.1.10", false},
+		{"10.0.0.1", false},
+		{"fd12:3456::1", false},
+		{"fe80::1", false},
+		{"127.0.0.1", false},
+		{"::1", false},
+	}
+	for _, tt := range tests {
+		if got := isPublic(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isPublic(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestDetectSkipsPrivateEndpointAnswers(t *testing.T) {
+	echo := func(ip string) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, ip)
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	private := echo("10.0.0.1")
+	public := echo("203.0.113.7")
+
+	d := &Detector{Endpoints: []string{private.URL}}
+	if ip, err := d.Detect(context.Background(), IPv4); err == nil {
+		t.Fatalf("Detect() = %s, want an error for a private address", ip)
+	}
+
+	d = &Detector{Endpoints: []string{private.URL, public.URL}}
+	ip, err := d.Detect(context.Background(), IPv4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip.String() != "203.0.113.7" {
+		t.Errorf("Detect() = %s, want 203.0.113.7", ip)
+	}
+}
@@ -0,0 +1,109 @@
+package ddns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+)
+
+// RecordStore is the part of the Cloudflare client the updater uses.
+type RecordStore interface {
+	FindDNSRecord(ctx context.Context, name, content string, recordType string) ([]cloudflare.DNSRecord, error)
+	AddDNSRecord(ctx context.Context, recordType, name, content string, ttl int, priority *uint16, proxied bool) (*cloudflare.DNSRecord, error)
+	UpdateDNSRecord(ctx context.Context, recordID, recordType, name, content string, ttl int, priority *uint16, proxied bool) (*cloudflare.DNSRecord, error)
+}
+
+// Updater points a record name at the host's current public addresses.
+type Updater struct {
+	Store    RecordStore
+	Detector *Detector
+	Logger   *slog.Logger
+
+	// Name is the fully-qualified record name to maintain.
+	Name     string
+	Families []Family
+
+	// TTL and Proxied are only used when a record has to be created;
+	// existing records keep their settings.
+	TTL     int
+	Proxied bool
+}
+
+// Sync updates every configured family once. It returns the errors of all
+// families that failed, joined.
+func (u *Updater) Sync(ctx context.Context) error {
+	var errs []error
+	for _, family := range u.Families {
+		if err := u.syncFamily(ctx, family); err != nil {
+			u.Logger.Error("update failed", "name", u.Name, "type", family.RecordType(), "error", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (u *Updater) syncFamily(ctx context.Context, family Family) error {
+	recordType := family.RecordType()
+
+	ip, err := u.Detector.Detect(ctx, family)
+	if err != nil {
+		return err
+	}
+	current := ip.String()
+
+	records, err := u.Store.FindDNSRecord(ctx, u.Name, "", recordType)
+	if err != nil {
+		return err
+	}
+
+	switch len(records) {
+	case 0:
+		record, err := u.Store.AddDNSRecord(ctx, recordType, u.Name, current, u.TTL, nil, u.Proxied)
+		if err != nil {
+			return err
+		}
+		u.Logger.Info("record created", "name", record.Name, "type", recordType, "ip", current, "id", record.ID)
+		return nil
+	case 1:
+	default:
+		return fmt.Errorf("found %d %s records for %s, refusing to choose one", len(records), recordType, u.Name)
+	}
+
+	record := records[0]
+	if previous := net.ParseIP(record.Content); previous != nil && previous.Equal(ip) {
+		u.Logger.Debug("record unchanged", "name", record.Name, "type", recordType, "ip", current)
+		return nil
+	}
+
+	proxied := record.Proxied != nil && *record.Proxied
+	if _, err := u.Store.UpdateDNSRecord(ctx, record.ID, recordType, record.Name, current, record.TTL, record.Priority, proxied); err != nil {
+		return err
+	}
+	u.Logger.Info("record updated", "name", record.Name, "type", recordType, "old_ip", record.Content, "ip", current)
+	return nil
+}
+
+// Run syncs immediately and then every interval until ctx is cancelled.
+// Failed syncs are logged and retried on the next tick.
+func (u *Updater) Run(ctx context.Context, interval time.Duration) error {
+	u.Logger.Info("starting", "name", u.Name, "interval", interval.String())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = u.Sync(ctx)
+
+		select {
+		case <-ctx.Done():
+			u.Logger.Info("shutting down", "reason", context.Cause(ctx).Error())
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package ddns
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+)
+
+type fakeStore struct {
+	records []cloudflare.DNSRecord
+	added   []string
+	updated []string
+}
+
+func (f *fakeStore) FindDNSRecord(ctx context.Context, name, content string, recordType string) ([]cloudflare.DNSRecord, error) {
+	var out []cloudflare.DNSRecord
+	for _, r := range f.records {
+		if r.Name == name && r.Type == recordType {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) AddDNSRecord(ctx context.Context, recordType, name, content string, ttl int, priority *uint16, proxied bool) (*cloudflare.DNSRecord, error) {
+	f.added = append(f.added, content)
+	return &cloudflare.DNSRecord{ID: "new", Type: recordType, Name: name, Content: content}, nil
+}
+
+func (f *fakeStore) UpdateDNSRecord(ctx context.Context, recordID, recordType, name, content string, ttl int, priority *uint16, proxied bool) (*cloudflare.DNSRecord, error) {
+	f.updated = append(f.updated, fmt.Sprintf("%s=%s", recordID, content))
+	return &cloudflare.DNSRecord{ID: recordID, Type: recordType, Name: name, Content: content}, nil
+}
+
+func TestUpdaterSync(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "203.0.113.7")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		records     []cloudflare.DNSRecord
+		wantAdded   int
+		wantUpdated []string
+	}{
+		{
+			name:      "creates missing record",
+			wantAdded: 1,
+		},
+		{
+			name:    "leaves matching record alone",
+			records: []cloudflare.DNSRecord{{ID: "r1", Type: "A", Name: "home.example.com", Content: "203.0.113.7"}},
+		},
+		{
+			name:        "updates changed record",
+			records:     []cloudflare.DNSRecord{{ID: "r1", Type: "A", Name: "home.example.com", Content: "198.51.100.1"}},
+			wantUpdated: []string{"r1=203.0.113.7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{records: tt.records}
+			u := &Updater{
+				Store:    store,
+				Detector: &Detector{Endpoints: []string{server.URL}},
+				Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+				Name:     "home.example.com",
+				Families: []Family{IPv4},
+				TTL:      1,
+			}
+			if err := u.Sync(context.Background()); err != nil {
+				t.Fatalf("Sync() error = %v", err)
+			}
+			if len(store.added) != tt.wantAdded {
+				t.Errorf("added %d records, want %d", len(store.added), tt.wantAdded)
+			}
+			if fmt.Sprint(store.updated) != fmt.Sprint(tt.wantUpdated) {
+				t.Errorf("updated = %v, want %v", store.updated, tt.wantUpdated)
+			}
+		})
+	}
+}
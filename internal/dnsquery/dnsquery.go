@@ -0,0 +1,227 @@
+// Package dnsquery sends DNS queries directly to a chosen nameserver,
+// bypassing the system resolver and its cache.
+package dnsquery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultTimeout bounds a single query when the context has no deadline.
+const DefaultTimeout = 5 * time.Second
+
+// Answer is the result of a query to one server.
+type Answer struct {
+	// Values holds the answers of the queried type in a normalized text
+	// form (see Normalize), sorted.
+	Values []string
+	// Rcode is the DNS response code, e.g. "NOERROR" or "NXDOMAIN".
+	Rcode string
+	// Authoritative reports whether the AA flag was set.
+	Authoritative bool
+	TTL           uint32
+}
+
+// Type returns the query type for a record type name such as "TXT".
+func Type(recordType string) (uint16, error) {
+	t, ok := dns.StringToType[strings.ToUpper(recordType)]
+	if !ok {
+		return 0, fmt.Errorf("unsupported record type %q", recordType)
+	}
+	return t, nil
+}
+
+// Query asks server (a host or host:port) for name/qtype over UDP, retrying
+// over TCP when the response is truncated.
+func Query(ctx context.Context, server, name string, qtype uint16) (*Answer, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = true
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+		defer cancel()
+	}
+
+	client := &dns.Client{Net: "udp"}
+	resp, _, err := client.ExchangeContext(ctx, msg, server)
+	if err == nil && resp.Truncated {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, msg, server)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query %s %s @%s: %w", dns.TypeToString[qtype], name, server, err)
+	}
+
+	answer := &Answer{
+		Rcode:         dns.RcodeToString[resp.Rcode],
+		Authoritative: resp.Authoritative,
+	}
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype != qtype {
+			continue
+		}
+		answer.Values = append(answer.Values, Normalize(rr))
+		answer.TTL = rr.Header().Ttl
+	}
+	sort.Strings(answer.Values)
+	return answer, nil
+}
+
+// Normalize renders a resource record's data the way the Cloudflare API
+// stores record content: names without the trailing dot, TXT strings
+// concatenated, MX as "preference host".
+func Normalize(rr dns.RR) string {
+	switch v := rr.(type) {
+	case *dns.A:
+		return v.A.String()
+	case *dns.AAAA:
+		return v.AAAA.String()
+	case *dns.CNAME:
+		return NormalizeName(v.Target)
+	case *dns.NS:
+		return NormalizeName(v.Ns)
+	case *dns.PTR:
+		return NormalizeName(v.Ptr)
+	case *dns.TXT:
+		return strings.Join(v.Txt, "")
+	case *dns.MX:
+		return strconv.Itoa(int(v.Preference)) + " " + NormalizeName(v.Mx)
+	case *dns.SRV:
+		return fmt.Sprintf("%d %d %d %s", v.Priority, v.Weight, v.Port, NormalizeName(v.Target))
+	case *dns.CAA:
+		return fmt.Sprintf("%d %s %q", v.Flag, v.Tag, v.Value)
+	}
+	fields := strings.Fields(rr.String())
+	if len(fields) > 4 {
+		return strings.Join(fields[4:], " ")
+	}
+	return rr.String()
+}
+
+// NormalizeName lower-cases a domain name and strips the trailing dot.
+func NormalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// UnquoteTXT strips the surrounding quotes the Cloudflare API may return
+// around TXT content and joins multiple quoted strings.
+func UnquoteTXT(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, `"`) {
+		return content
+	}
+	var b strings.Builder
+	for _, part := range strings.SplitAfter(content, `" `) {
+		part = strings.TrimSpace(part)
+		if unquoted, err := strconv.Unquote(part); err == nil {
+			b.WriteString(unquoted)
+		} else {
+			b.WriteString(strings.Trim(part, `"`))
+		}
+	}
+	return b.String()
+}
+
+// Resolver sends queries to a fixed server.
+type Resolver struct {
+	Server string
+}
+
+// Query asks the resolver for name/recordType.
+func (r Resolver) Query(ctx context.Context, name, recordType string) (*Answer, error) {
+	qtype, err := Type(recordType)
+	if err != nil {
+		return nil, err
+	}
+	return Query(ctx, r.Server, name, qtype)
+}
+
+// LookupTXT returns the TXT values at name. A missing name is an error.
+func (r Resolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	answer, err := r.Query(ctx, name, "TXT")
+	if err != nil {
+		return nil, err
+	}
+	if answer.Rcode != "NOERROR" {
+		return nil, fmt.Errorf("%s: %s", name, answer.Rcode)
+	}
+	return answer.Values, nil
+}
+
+// LookupIP returns the IPv4 and IPv6 addresses of name. A name without
+// addresses yields an empty list; a missing name is an error.
+func (r Resolver) LookupIP(ctx context.Context, name string) ([]string, error) {
+	var addrs []string
+	for _, recordType := range []string{"A", "AAAA"} {
+		answer, err := r.Query(ctx, name, recordType)
+		if err != nil {
+			return nil, err
+		}
+		if answer.Rcode != "NOERROR" {
+			return nil, fmt.Errorf("%s: %s", name, answer.Rcode)
+		}
+		addrs = append(addrs, answer.Values...)
+	}
+	return addrs, nil
+}
+
+// LookupMX returns the mail exchanger host names of name, most preferred
+// first.
+func (r Resolver) LookupMX(ctx context.Context, name string) ([]string, error) {
+	answer, err := r.Query(ctx, name, "MX")
+	if err != nil {
+		return nil, err
+	}
+	if answer.Rcode != "NOERROR" {
+		return nil, fmt.Errorf("%s: %s", name, answer.Rcode)
+	}
+	type mx struct {
+		pref int
+		host string
+	}
+	var mxs []mx
+	for _, v := range answer.Values {
+		pref, host, ok := strings.Cut(v, " ")
+		if !ok {
+			continue
+		}
+		n, _ := strconv.Atoi(pref)
+		mxs = append(mxs, mx{n, host})
+	}
+	sort.SliceStable(mxs, func(i, j int) bool { return mxs[i].pref < mxs[j].pref })
+	hosts := make([]string, len(mxs))
+	for i, m := range mxs {
+		hosts[i] = m.host
+	}
+	return hosts, nil
+}
+
+// QuoteTXT renders content as the quoted character-strings of a TXT
+// record, split into the 255-byte chunks DNS allows. Receivers concatenate
+// the strings without separators. Content short enough for one string is
+// returned unchanged.
+func QuoteTXT(content string) string {
+	if len(content) <= 255 {
+		return content
+	}
+	var parts []string
+	for len(content) > 0 {
+		n := min(len(content), 255)
+		parts = append(parts, strconv.Quote(content[:n]))
+		content = content[n:]
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,37 @@
+package dnsquery
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnquoteTXT(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"v=spf1 -all", "v=spf1 -all"},
+		{`"v=spf1 -all"`, "v=spf1 -all"},
+		{`"v=DKIM1; p=abc" "def"`, "v=DKIM1; p=abcdef"},
+		{`"say \"hi\""`, `say "hi"`},
+	}
+	for _, tt := range tests {
+		if got := UnquoteTXT(tt.in); got != tt.want {
+			t.Errorf("UnquoteTXT(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteTXT(t *testing.T) {
+	short := "v=spf1 -all"
+	if got := QuoteTXT(short); got != short {
+		t.Errorf("QuoteTXT(short) = %q", got)
+	}
+	long := strings.Repeat("a", 300)
+	quoted := QuoteTXT(long)
+	if want := `"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("a", 45) + `"`; quoted != want {
+		t.Errorf("QuoteTXT(long) = %q", quoted)
+	}
+	if got := UnquoteTXT(quoted); got != long {
+		t.Errorf("UnquoteTXT(QuoteTXT(long)) lost data")
+	}
+}
@@ -0,0 +1,218 @@
+// Package dnssec parses DS records and checks the delegation signer records
+// published in a parent zone against the ones Cloudflare expects.
+package dnssec
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// DS is a delegation signer record.
+type DS struct {
+	KeyTag     int    `json:"key_tag"`
+	Algorithm  int    `json:"algorithm"`
+	DigestType int    `json:"digest_type"`
+	Digest     string `json:"digest"`
+}
+
+// ParseDS parses the data of a DS record, "2371 13 2 C1A9...", optionally
+// preceded by owner, TTL, class and type as in a zone file line.
+func ParseDS(s string) (DS, error) {
+	fields := strings.Fields(s)
+	for i, f := range fields {
+		if strings.EqualFold(f, "DS") {
+			fields = fields[i+1:]
+			break
+		}
+	}
+	if len(fields) < 4 {
+		return DS{}, fmt.Errorf("invalid DS record %q", s)
+	}
+	var nums [3]int
+	for i := range nums {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return DS{}, fmt.Errorf("invalid DS record %q: %q is not a number", s, fields[i])
+		}
+		nums[i] = n
+	}
+	return DS{
+		KeyTag:     nums[0],
+		Algorithm:  nums[1],
+		DigestType: nums[2],
+		Digest:     strings.ToUpper(strings.Join(fields[3:], "")),
+	}, nil
+}
+
+// String renders the record data as in a zone file.
+func (d DS) String() string {
+	return fmt.Sprintf("%d %d %d %s", d.KeyTag, d.Algorithm, d.DigestType, d.Digest)
+}
+
+// Equal reports whether two DS records are the same, ignoring the case of
+// the digest.
+func (d DS) Equal(o DS) bool {
+	return d.KeyTag == o.KeyTag && d.Algorithm == o.Algorithm &&
+		d.DigestType == o.DigestType && strings.EqualFold(d.Digest, o.Digest)
+}
+
+// DNSKEY is the public key a DS record is a digest of.
+type DNSKEY struct {
+	Flags     int
+	Algorithm int
+	// PublicKey is the base64-encoded key as shown in the zone file.
+	PublicKey string
+}
+
+// Digest computes the DS digest of the key published at owner for the
+// given digest type (RFC 4034 section 5.1.4).
+func (k DNSKEY) Digest(owner string, digestType int) (string, error) {
+	var h hash.Hash
+	switch digestType {
+	case 1:
+		h = sha1.New()
+	case 2:
+		h = sha256.New()
+	case 4:
+		h = sha512.New384()
+	default:
+		return "", fmt.Errorf("unsupported digest type %d", digestType)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(k.PublicKey), ""))
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+	name, err := wireName(owner)
+	if err != nil {
+		return "", err
+	}
+	h.Write(name)
+	h.Write(binary.BigEndian.AppendUint16(nil, uint16(k.Flags)))
+	h.Write([]byte{3, byte(k.Algorithm)})
+	h.Write(key)
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// wireName encodes a domain name in canonical (lower case) wire format.
+func wireName(name string) ([]byte, error) {
+	var out []byte
+	for _, label := range strings.Split(strings.TrimSuffix(strings.ToLower(name), "."), ".") {
+		if label == "" || len(label) > 63 {
+			return nil, fmt.Errorf("invalid domain name %q", name)
+		}
+		out = append(out, byte(len(label)))
+		out = append(out, label...)
+	}
+	return append(out, 0), nil
+}
+
+var algorithms = map[int]string{
+	5:  "RSA/SHA-1",
+	7:  "RSASHA1-NSEC3-SHA1",
+	8:  "RSA/SHA-256",
+	10: "RSA/SHA-512",
+	13: "ECDSA Curve P-256 with SHA-256",
+	14: "ECDSA Curve P-384 with SHA-384",
+	15: "Ed25519",
+	16: "Ed448",
+}
+
+var digestTypes = map[int]string{
+	1: "SHA-1",
+	2: "SHA-256",
+	4: "SHA-384",
+}
+
+// AlgorithmName returns the name of a DNSSEC algorithm number, as most
+// registrars list it.
+func AlgorithmName(n int) string {
+	if name, ok := algorithms[n]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// DigestTypeName returns the name of a DS digest type number.
+func DigestTypeName(n int) string {
+	if name, ok := digestTypes[n]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Status is the outcome of comparing the parent zone's DS records with the
+// expected one.
+type Status int
+
+const (
+	// Match means the parent publishes only DS records for the expected
+	// key, in the expected digest type or another one.
+	Match Status = iota
+	// MatchWithExtra means the parent publishes a DS record for the
+	// expected key and others that do not match it, e.g. left over from a previous DNS provider.
+	MatchWithExtra
+	// Missing means the parent publishes no DS records, so the zone is
+	// not validated.
+	Missing
+	// Mismatch means the parent publishes DS records, none of them the
+	// expected one, so validating resolvers fail to resolve the zone.
+	Mismatch
+)
+
+func (s Status) String() string {
+	switch s {
+	case Match:
+		return "match"
+	case MatchWithExtra:
+		return "match with extra records"
+	case Missing:
+		return "missing"
+	}
+	return "mismatch"
+}
+
+// Verify compares the DS records published for zone in the parent zone
+// with the expected one. A published record for the same key tag and
+// algorithm but another digest type matches when its digest is the one
+// computed from key; without a key only the expected record matches. It
+// also returns the published records that do not match.
+func Verify(zone string, expected DS, key *DNSKEY, published []DS) (Status, []DS) {
+	if len(published) == 0 {
+		return Missing, nil
+	}
+	found := false
+	var extra []DS
+	for _, ds := range published {
+		if matches(zone, expected, key, ds) {
+			found = true
+			continue
+		}
+		extra = append(extra, ds)
+	}
+	switch {
+	case !found:
+		return Mismatch, extra
+	case len(extra) > 0:
+		return MatchWithExtra, extra
+	}
+	return Match, nil
+}
+
+func matches(zone string, expected DS, key *DNSKEY, ds DS) bool {
+	if ds.KeyTag != expected.KeyTag || ds.Algorithm != expected.Algorithm {
+		return false
+	}
+	if ds.DigestType == expected.DigestType || key == nil {
+		return ds.Equal(expected)
+	}
+	digest, err := key.Digest(zone, ds.DigestType)
+	return err == nil && strings.EqualFold(digest, ds.Digest)
+}
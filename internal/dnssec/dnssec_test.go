@@ -0,0 +1,84 @@
+package dnssec
+
+import "testing"
+
+func TestParseDS(t *testing.T) {
+	want := DS{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "C1A9D0C4AB36"}
+	for _, s := range []string{
+		"2371 13 2 c1a9d0c4ab36",
+		"example.com. 3600 IN DS 2371 13 2 C1A9D0C4AB36",
+		"2371 13 2 C1A9D0 C4AB36",
+	} {
+		got, err := ParseDS(s)
+		if err != nil {
+			t.Errorf("ParseDS(%q): %v", s, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseDS(%q) = %+v, want %+v", s, got, want)
+		}
+	}
+	for _, s := range []string{"", "2371 13 2", "example.com. IN DS x 13 2 AB"} {
+		if _, err := ParseDS(s); err == nil {
+			t.Errorf("ParseDS(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	expected := DS{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "AB12"}
+	old := DS{KeyTag: 4711, Algorithm: 8, DigestType: 2, Digest: "CD34"}
+	lower := DS{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "ab12"}
+
+	tests := []struct {
+		published []DS
+		want      Status
+		extra     int
+	}{
+		{nil, Missing, 0},
+		{[]DS{lower}, Match, 0},
+		{[]DS{expected, old}, MatchWithExtra, 1},
+		{[]DS{old}, Mismatch, 1},
+	}
+	for _, tt := range tests {
+		got, extra := Verify("example.com", expected, nil, tt.published)
+		if got != tt.want || len(extra) != tt.extra {
+			t.Errorf("Verify(%v) = %s with %d extra, want %s with %d", tt.published, got, len(extra), tt.want, tt.extra)
+		}
+	}
+}
+
+func TestVerifyOtherDigestTypes(t *testing.T) {
+	// Key and digests from RFC 4034 section 5.4 and RFC 4509 section 2.3.
+	key := &DNSKEY{Flags: 256, Algorithm: 5, PublicKey: "AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw=="}
+	expected := DS{KeyTag: 60485, Algorithm: 5, DigestType: 2, Digest: "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A"}
+	sha1DS := DS{KeyTag: 60485, Algorithm: 5, DigestType: 1, Digest: "2BB183AF5F22588179A53B0A98631FAD1A292118"}
+	badSHA1 := DS{KeyTag: 60485, Algorithm: 5, DigestType: 1, Digest: "2BB183AF5F22588179A53B0A98631FAD1A292119"}
+
+	// Key and SHA-384 digest from RFC 6605 section 6.2.
+	p384 := &DNSKEY{Flags: 257, Algorithm: 14, PublicKey: "xKYaNhWdGOfJ+nPrL8/arkwf2EY3MDJ+SErKivBVSum1w/egsXvSADtNJhyem5RCOpgQ6K8X1DRSEkrbYQ+OB+v8/uX45NBwY8rp65F6Glur8I/mlVNgF6W/qTI37m40"}
+	p384Expected := DS{KeyTag: 10771, Algorithm: 14, DigestType: 2, Digest: "FDE87F87D3A32AD8781EB0D79AC02F80D1381CECDA3567C2352B4986645C2DD0"}
+	sha384DS := DS{KeyTag: 10771, Algorithm: 14, DigestType: 4, Digest: "72d7b62976ce06438e9c0bf319013cf801f09ecc84b8d7e9495f27e305c6a9b0563a9b5f4d288405c3008a946df983d6"}
+
+	tests := []struct {
+		name      string
+		zone      string
+		key       *DNSKEY
+		expected  DS
+		published []DS
+		want      Status
+		extra     int
+	}{
+		{"SHA-1 next to SHA-256", "dskey.example.com", key, expected, []DS{expected, sha1DS}, Match, 0},
+		{"SHA-1 only", "dskey.example.com", key, expected, []DS{sha1DS}, Match, 0},
+		{"wrong SHA-1 digest", "dskey.example.com", key, expected, []DS{expected, badSHA1}, MatchWithExtra, 1},
+		{"SHA-384 only", "example.net", p384, p384Expected, []DS{sha384DS}, Match, 0},
+		{"no key to check against", "example.net", nil, p384Expected, []DS{sha384DS}, Mismatch, 1},
+	}
+	for _, tt := range tests {
+		got, extra := Verify(tt.zone, tt.expected, tt.key, tt.published)
+		if got != tt.want || len(extra) != tt.extra {
+			t.Errorf("%s: Verify() = %s with %d extra, want %s with %d", tt.name, got, len(extra), tt.want, tt.extra)
+		}
+	}
+}
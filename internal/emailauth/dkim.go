@@ -0,0 +1,104 @@
+package emailauth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// DKIMName returns the name of the DKIM key record for selector in domain.
+func DKIMName(selector, domain string) string {
+	return selector + "._domainkey." + domain
+}
+
+// ParseDKIM parses and validates a DKIM key record.
+func ParseDKIM(txt string) (Tags, error) {
+	tags, err := ParseTags(txt)
+	if err != nil {
+		return nil, err
+	}
+	return tags, ValidateDKIM(tags)
+}
+
+// ValidateDKIM checks the tags of a DKIM key record against RFC 6376. The
+// v= tag is optional, but must come first when present.
+func ValidateDKIM(tags Tags) error {
+	p, ok := tags.Get("p")
+	if !ok {
+		return fmt.Errorf("missing required p= (public key) tag")
+	}
+	for i, tag := range tags {
+		switch tag.Name {
+		case "v":
+			if i != 0 || tag.Value != "DKIM1" {
+				return fmt.Errorf("v= must be first and equal DKIM1")
+			}
+		case "k":
+			if tag.Value != "rsa" && tag.Value != "ed25519" {
+				return fmt.Errorf("k: %q must be rsa or ed25519", tag.Value)
+			}
+		case "p":
+			if p == "" {
+				continue // an empty key means the key was revoked
+			}
+			if _, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(p), "")); err != nil {
+				return fmt.Errorf("p: public key is not valid base64")
+			}
+		case "t":
+			for _, flag := range strings.Split(tag.Value, ":") {
+				if flag != "y" && flag != "s" {
+					return fmt.Errorf("t: %q must be a colon-separated list of y and s", tag.Value)
+				}
+			}
+		case "h", "s", "n":
+		default:
+			return fmt.Errorf("unknown DKIM tag %q", tag.Name)
+		}
+	}
+	return nil
+}
+
+// DKIMFromPublicKey builds a DKIM key record from a PEM-encoded public key
+// (PKIX "PUBLIC KEY" or PKCS #1 "RSA PUBLIC KEY"). RSA keys are published
+// as DER SubjectPublicKeyInfo and Ed25519 keys as the raw 32-byte key, as
+// RFC 6376 and RFC 8463 require.
+func DKIMFromPublicKey(data []byte) (Tags, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM-encoded public key found")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q (expected a public key, not a private key)", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 1024 {
+			return nil, fmt.Errorf("RSA key is %d bits; DKIM verifiers require at least 1024", k.N.BitLen())
+		}
+		der, err := x509.MarshalPKIXPublicKey(k)
+		if err != nil {
+			return nil, err
+		}
+		return Tags{{"v", "DKIM1"}, {"k", "rsa"}, {"p", base64.StdEncoding.EncodeToString(der)}}, nil
+	case ed25519.PublicKey:
+		return Tags{{"v", "DKIM1"}, {"k", "ed25519"}, {"p", base64.StdEncoding.EncodeToString(k)}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (DKIM supports RSA and Ed25519)", key)
+	}
+}
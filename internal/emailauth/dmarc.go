@@ -0,0 +1,91 @@
+package emailauth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DMARCName returns the name of the DMARC record for domain.
+func DMARCName(domain string) string {
+	return "_dmarc." + domain
+}
+
+// IsDMARC reports whether txt looks like a DMARC record.
+func IsDMARC(txt string) bool {
+	return strings.HasPrefix(strings.TrimSpace(txt), "v=DMARC1")
+}
+
+// ParseDMARC parses and validates a DMARC record.
+func ParseDMARC(txt string) (Tags, error) {
+	tags, err := ParseTags(txt)
+	if err != nil {
+		return nil, err
+	}
+	return tags, ValidateDMARC(tags)
+}
+
+// ValidateDMARC checks the tags of a DMARC record against RFC 7489.
+func ValidateDMARC(tags Tags) error {
+	if err := tags.requireVersion("DMARC1"); err != nil {
+		return err
+	}
+	if len(tags) < 2 || tags[1].Name != "p" {
+		return fmt.Errorf("p= must directly follow v=DMARC1")
+	}
+	for _, tag := range tags[1:] {
+		if err := validateDMARCTag(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateDMARCTag(tag Tag) error {
+	switch tag.Name {
+	case "p", "sp", "np":
+		switch tag.Value {
+		case "none", "quarantine", "reject":
+			return nil
+		}
+		return fmt.Errorf("%s: %q must be none, quarantine or reject", tag.Name, tag.Value)
+	case "adkim", "aspf":
+		if tag.Value == "r" || tag.Value == "s" {
+			return nil
+		}
+		return fmt.Errorf("%s: %q must be r (relaxed) or s (strict)", tag.Name, tag.Value)
+	case "pct":
+		n, err := strconv.Atoi(tag.Value)
+		if err != nil || n < 0 || n > 100 {
+			return fmt.Errorf("pct: %q must be an integer from 0 to 100", tag.Value)
+		}
+	case "ri":
+		if n, err := strconv.ParseUint(tag.Value, 10, 32); err != nil || n == 0 {
+			return fmt.Errorf("ri: %q must be a positive number of seconds", tag.Value)
+		}
+	case "fo":
+		for _, opt := range strings.Split(tag.Value, ":") {
+			switch opt {
+			case "0", "1", "d", "s":
+			default:
+				return fmt.Errorf("fo: %q must be a colon-separated list of 0, 1, d and s", tag.Value)
+			}
+		}
+	case "rf":
+		if tag.Value != "afrf" {
+			return fmt.Errorf("rf: %q is not supported (only afrf)", tag.Value)
+		}
+	case "rua", "ruf":
+		return checkURIs(tag.Name, tag.Value, "mailto:")
+	case "v":
+		return fmt.Errorf("v= must be the first tag")
+	default:
+		return fmt.Errorf("unknown DMARC tag %q", tag.Name)
+	}
+	return nil
+}
+
+// NewDMARC returns a minimal DMARC record with the given policy.
+func NewDMARC(policy string) Tags {
+	return Tags{{"v", "DMARC1"}, {"p", policy}}
+}
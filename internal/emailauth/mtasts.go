@@ -0,0 +1,75 @@
+package emailauth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MTASTSName returns the name of the MTA-STS policy record for domain.
+func MTASTSName(domain string) string {
+	return "_mta-sts." + domain
+}
+
+// TLSRPTName returns the name of the SMTP TLS reporting record for domain.
+func TLSRPTName(domain string) string {
+	return "_smtp._tls." + domain
+}
+
+// PolicyURL returns where the MTA-STS policy file for domain must be served.
+func PolicyURL(domain string) string {
+	return "https://mta-sts." + domain + "/.well-known/mta-sts.txt"
+}
+
+// NewPolicyID returns a policy id derived from t. Senders refetch the policy
+// whenever the id changes, so it must change with every policy update.
+func NewPolicyID(t time.Time) string {
+	return t.UTC().Format("20060102T150405")
+}
+
+// ParseMTASTS parses and validates an MTA-STS policy record.
+func ParseMTASTS(txt string) (Tags, error) {
+	tags, err := ParseTags(txt)
+	if err != nil {
+		return nil, err
+	}
+	return tags, ValidateMTASTS(tags)
+}
+
+// ValidateMTASTS checks an MTA-STS policy record against RFC 8461.
+func ValidateMTASTS(tags Tags) error {
+	if err := tags.requireVersion("STSv1"); err != nil {
+		return err
+	}
+	id, ok := tags.Get("id")
+	if !ok {
+		return fmt.Errorf("missing required id= tag")
+	}
+	if id == "" || len(id) > 32 || strings.IndexFunc(id, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) >= 0 {
+		return fmt.Errorf("id: %q must be 1-32 letters and digits", id)
+	}
+	return nil
+}
+
+// ParseTLSRPT parses and validates an SMTP TLS reporting record.
+func ParseTLSRPT(txt string) (Tags, error) {
+	tags, err := ParseTags(txt)
+	if err != nil {
+		return nil, err
+	}
+	return tags, ValidateTLSRPT(tags)
+}
+
+// ValidateTLSRPT checks an SMTP TLS reporting record against RFC 8460.
+func ValidateTLSRPT(tags Tags) error {
+	if err := tags.requireVersion("TLSRPTv1"); err != nil {
+		return err
+	}
+	rua, ok := tags.Get("rua")
+	if !ok {
+		return fmt.Errorf("missing required rua= tag")
+	}
+	return checkURIs("rua", rua, "mailto:", "https://")
+}
@@ -0,0 +1,128 @@
+// Package emailauth parses, validates and builds the tag-list TXT records
+// used by DMARC (RFC 7489), DKIM (RFC 6376), MTA-STS (RFC 8461) and SMTP
+// TLS reporting (RFC 8460).
+package emailauth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tag is a single name=value pair of a tag-list record.
+type Tag struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Tags is a tag-list record such as "v=DMARC1; p=reject". Order is kept so
+// that rewriting a record only changes the tags that were edited.
+type Tags []Tag
+
+// ParseTags splits a "name=value; name=value" record. Tag names are
+// lower-cased except for "v", whose value is compared case-sensitively by
+// callers.
+func ParseTags(s string) (Tags, error) {
+	var tags Tags
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed tag %q (expected name=value)", part)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			return nil, fmt.Errorf("malformed tag %q (empty name)", part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate tag %q", name)
+		}
+		seen[name] = true
+		tags = append(tags, Tag{name, strings.TrimSpace(value)})
+	}
+	return tags, nil
+}
+
+// Get returns the value of the named tag.
+func (t Tags) Get(name string) (string, bool) {
+	for _, tag := range t {
+		if tag.Name == name {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set replaces the named tag's value, appending it when absent.
+func (t *Tags) Set(name, value string) {
+	for i, tag := range *t {
+		if tag.Name == name {
+			(*t)[i].Value = value
+			return
+		}
+	}
+	*t = append(*t, Tag{name, value})
+}
+
+// Delete removes the named tag.
+func (t *Tags) Delete(name string) {
+	kept := (*t)[:0]
+	for _, tag := range *t {
+		if tag.Name != name {
+			kept = append(kept, tag)
+		}
+	}
+	*t = kept
+}
+
+func (t Tags) String() string {
+	parts := make([]string, len(t))
+	for i, tag := range t {
+		parts[i] = tag.Name + "=" + tag.Value
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ParseAssignments parses command-line style "name=value" arguments.
+func ParseAssignments(args []string) (Tags, error) {
+	var tags Tags
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid argument %q (expected tag=value)", arg)
+		}
+		tags = append(tags, Tag{strings.ToLower(name), value})
+	}
+	return tags, nil
+}
+
+// requireVersion checks that the first tag is v=want.
+func (t Tags) requireVersion(want string) error {
+	if len(t) == 0 || t[0].Name != "v" {
+		return fmt.Errorf("record must start with v=%s", want)
+	}
+	if t[0].Value != want {
+		return fmt.Errorf("unsupported version v=%s (want v=%s)", t[0].Value, want)
+	}
+	return nil
+}
+
+// checkURIs validates a comma-separated list of reporting URIs.
+func checkURIs(tag, value string, schemes ...string) error {
+	for _, uri := range strings.Split(value, ",") {
+		uri = strings.TrimSpace(uri)
+		ok := false
+		for _, scheme := range schemes {
+			if rest, found := strings.CutPrefix(strings.ToLower(uri), scheme); found && rest != "" {
+				ok = true
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%s: %q is not a %s URI", tag, uri, strings.Join(schemes, " or "))
+		}
+	}
+	return nil
+}
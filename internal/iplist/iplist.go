@@ -0,0 +1,78 @@
+// Package iplist reads files of IP addresses and CIDR ranges for Cloudflare
+// IP lists.
+package iplist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+)
+
+// LineError is a problem with one line of a file.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Normalize returns the form of an IP list item the API stores: an IPv4
+// address, or a CIDR range with its host bits cleared. IPv4 ranges may be
+// /8 to /32 and IPv6 ranges /12 to /64; single IPv6 addresses are not
+// accepted by the API. A /32 range is returned as an address.
+func Normalize(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if addr, err := netip.ParseAddr(s); err == nil {
+		addr = addr.Unmap()
+		if addr.Is6() {
+			return "", fmt.Errorf("single IPv6 addresses are not supported, use a range such as %s", netip.PrefixFrom(addr, 64).Masked())
+		}
+		return addr.String(), nil
+	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return "", fmt.Errorf("%q is not an IP address or CIDR range", s)
+	}
+	bits := prefix.Bits()
+	switch {
+	case prefix.Addr().Is4() && bits == 32:
+		return prefix.Addr().String(), nil
+	case prefix.Addr().Is4() && bits < 8:
+		return "", fmt.Errorf("range %s is too large (IPv4 ranges must be /8 to /32)", s)
+	case prefix.Addr().Is6() && (bits < 12 || bits > 64):
+		return "", fmt.Errorf("unsupported range %s (IPv6 ranges must be /12 to /64)", s)
+	}
+	return prefix.Masked().String(), nil
+}
+
+// Read reads one IP address or CIDR range per line, normalized as by
+// Normalize and without duplicates. Blank lines and everything after a #
+// are ignored. Lines that fail to parse are returned as LineErrors
+// alongside the valid entries.
+func Read(r io.Reader) ([]string, []LineError, error) {
+	var ips []string
+	var errs []LineError
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		ip, err := Normalize(text)
+		if err != nil {
+			errs = append(errs, LineError{Line: line, Err: err})
+			continue
+		}
+		if !seen[ip] {
+			seen[ip] = true
+			ips = append(ips, ip)
+		}
+	}
+	return ips, errs, scanner.Err()
+}
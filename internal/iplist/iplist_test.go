@@ -0,0 +1,56 @@
+package iplist
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"192.0.2.1", "192.0.2.1", false},
+		{" 192.0.2.1/32 ", "192.0.2.1", false},
+		{"192.0.2.77/24", "192.0.2.0/24", false},
+		{"10.0.0.0/8", "10.0.0.0/8", false},
+		{"2001:DB8::/48", "2001:db8::/48", false},
+		{"::ffff:192.0.2.1", "192.0.2.1", false},
+		{"10.0.0.0/7", "", true},
+		{"2001:db8::1", "", true},
+		{"2001:db8::/96", "", true},
+		{"example.com", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Normalize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	in := `# threat intel feed
+203.0.113.5   # scanner
+198.51.100.0/24
+
+203.0.113.5
+not-an-ip
+`
+	ips, errs, err := Read(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"203.0.113.5", "198.51.100.0/24"}; !reflect.DeepEqual(ips, want) {
+		t.Errorf("ips = %v, want %v", ips, want)
+	}
+	if len(errs) != 1 || errs[0].Line != 6 {
+		t.Errorf("errs = %v, want one on line 6", errs)
+	}
+}
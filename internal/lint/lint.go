@@ -0,0 +1,350 @@
+// Package lint audits a zone's DNS records for common mistakes and risks.
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/dnsquery"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/spf"
+)
+
+// Severity ranks findings.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	}
+	return "info"
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// ParseSeverity parses "info", "warning" or "error".
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "info":
+		return Info, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	}
+	return Info, fmt.Errorf("unknown severity %q (use info, warning or error)", s)
+}
+
+// Finding is a single problem found in the zone.
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Check    string   `json:"check"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Resolver answers the queries that look outside the zone.
+type Resolver interface {
+	Query(ctx context.Context, name, recordType string) (*dnsquery.Answer, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// Linter runs all checks against a zone's records.
+type Linter struct {
+	Zone    string
+	Records []cloudflare.DNSRecord
+	// Resolver is used for checks that need live DNS. When nil those
+	// checks only use the zone's own records.
+	Resolver Resolver
+}
+
+// Run executes every check and returns the findings, most severe first.
+func (l *Linter) Run(ctx context.Context) []Finding {
+	var findings []Finding
+	for _, check := range []func(context.Context) []Finding{
+		l.checkDuplicates,
+		l.checkCNAMETargets,
+		l.checkMXNSTargets,
+		l.checkSPF,
+		l.checkDMARC,
+		l.checkOriginExposure,
+		l.checkPrivateAddresses,
+	} {
+		findings = append(findings, check(ctx)...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity > findings[j].Severity
+		}
+		return findings[i].Name < findings[j].Name
+	})
+	return findings
+}
+
+func (l *Linter) checkDuplicates(ctx context.Context) []Finding {
+	var findings []Finding
+	seen := map[string]bool{}
+	for _, r := range l.Records {
+		key := strings.ToLower(r.Type + "|" + r.Name + "|" + dnsquery.UnquoteTXT(r.Content))
+		if seen[key] {
+			findings = append(findings, Finding{Warning, "duplicate", r.Name, r.Type,
+				fmt.Sprintf("duplicate %s record with content %q", r.Type, r.Content)})
+		}
+		seen[key] = true
+	}
+	return findings
+}
+
+// takeoverSuffixes are hosting services where a CNAME left behind after the
+// resource is deleted can be claimed by someone else.
+var takeoverSuffixes = []string{
+	".s3.amazonaws.com", ".cloudfront.net", ".elasticbeanstalk.com",
+	".azurewebsites.net", ".cloudapp.net", ".cloudapp.azure.com",
+	".trafficmanager.net", ".blob.core.windows.net", ".azureedge.net",
+	".herokuapp.com", ".herokudns.com", ".github.io", ".gitlab.io",
+	".netlify.app", ".pantheonsite.io", ".wpengine.com", ".ghost.io",
+	".surge.sh", ".bitbucket.io", ".zendesk.com", ".readthedocs.io",
+	".myshopify.com", ".unbouncepages.com", ".fastly.net",
+}
+
+func takeoverProvider(target string) (string, bool) {
+	if strings.Contains(target, ".s3-website") {
+		return "s3-website", true
+	}
+	for _, suffix := range takeoverSuffixes {
+		if strings.HasSuffix(target, suffix) {
+			return strings.TrimPrefix(suffix, "."), true
+		}
+	}
+	return "", false
+}
+
+func (l *Linter) checkCNAMETargets(ctx context.Context) []Finding {
+	var findings []Finding
+	for _, r := range l.Records {
+		if !strings.EqualFold(r.Type, "CNAME") {
+			continue
+		}
+		target := dnsquery.NormalizeName(r.Content)
+
+		if cloudflare.InZone(target, l.Zone) {
+			if !l.hasName(target) {
+				findings = append(findings, Finding{Error, "dangling-cname", r.Name, r.Type,
+					fmt.Sprintf("CNAME target %s does not exist in the zone", target)})
+			}
+			continue
+		}
+
+		provider, risky := takeoverProvider(target)
+		if l.Resolver == nil {
+			continue
+		}
+		answer, err := l.Resolver.Query(ctx, target, "A")
+		if err != nil {
+			findings = append(findings, Finding{Info, "dangling-cname", r.Name, r.Type,
+				fmt.Sprintf("could not resolve CNAME target %s: %v", target, err)})
+			continue
+		}
+		switch {
+		case answer.Rcode == "NXDOMAIN" && risky:
+			findings = append(findings, Finding{Error, "subdomain-takeover", r.Name, r.Type,
+				fmt.Sprintf("CNAME target %s does not exist on %s; the name can likely be claimed by anyone", target, provider)})
+		case answer.Rcode == "NXDOMAIN":
+			findings = append(findings, Finding{Error, "dangling-cname", r.Name, r.Type,
+				fmt.Sprintf("CNAME target %s does not exist (NXDOMAIN)", target)})
+		case risky:
+			findings = append(findings, Finding{Info, "subdomain-takeover", r.Name, r.Type,
+				fmt.Sprintf("CNAME points at %s; make sure the resource behind %s still belongs to you", provider, target)})
+		}
+	}
+	return findings
+}
+
+func (l *Linter) checkMXNSTargets(ctx context.Context) []Finding {
+	var findings []Finding
+	for _, r := range l.Records {
+		if !strings.EqualFold(r.Type, "MX") && !strings.EqualFold(r.Type, "NS") {
+			continue
+		}
+		target := dnsquery.NormalizeName(r.Content)
+		isCNAME := false
+		if cloudflare.InZone(target, l.Zone) {
+			isCNAME = l.hasRecord(target, "CNAME")
+		} else if l.Resolver != nil {
+			if answer, err := l.Resolver.Query(ctx, target, "CNAME"); err == nil && len(answer.Values) > 0 {
+				isCNAME = true
+			}
+		}
+		if isCNAME {
+			findings = append(findings, Finding{Error, "target-is-cname", r.Name, r.Type,
+				fmt.Sprintf("%s target %s is a CNAME, which RFC 2181 forbids", r.Type, target)})
+		}
+	}
+	return findings
+}
+
+func (l *Linter) checkSPF(ctx context.Context) []Finding {
+	var findings []Finding
+	byName := map[string][]string{}
+	var names []string
+	for _, r := range l.Records {
+		if !strings.EqualFold(r.Type, "TXT") {
+			continue
+		}
+		txt := dnsquery.UnquoteTXT(r.Content)
+		if !spf.IsSPF(txt) {
+			continue
+		}
+		if _, ok := byName[r.Name]; !ok {
+			names = append(names, r.Name)
+		}
+		byName[r.Name] = append(byName[r.Name], txt)
+	}
+
+	for _, name := range names {
+		policies := byName[name]
+		if len(policies) > 1 {
+			findings = append(findings, Finding{Error, "spf-multiple", name, "TXT",
+				fmt.Sprintf("%d SPF records; receivers treat this as a permanent error", len(policies))})
+			continue
+		}
+		record, err := spf.Parse(policies[0])
+		if err != nil {
+			findings = append(findings, Finding{Error, "spf-syntax", name, "TXT", err.Error()})
+			continue
+		}
+		if l.Resolver == nil {
+			continue
+		}
+		count, err := spf.CountLookups(ctx, record, l.Resolver.LookupTXT)
+		switch {
+		case err != nil:
+			findings = append(findings, Finding{Warning, "spf-lookups", name, "TXT",
+				fmt.Sprintf("could not evaluate includes: %v", err)})
+		case count > spf.MaxLookups:
+			findings = append(findings, Finding{Error, "spf-lookups", name, "TXT",
+				fmt.Sprintf("SPF needs %d DNS lookups, more than the limit of %d", count, spf.MaxLookups)})
+		case count > spf.MaxLookups-2:
+			findings = append(findings, Finding{Info, "spf-lookups", name, "TXT",
+				fmt.Sprintf("SPF needs %d of %d allowed DNS lookups", count, spf.MaxLookups)})
+		}
+	}
+	return findings
+}
+
+func (l *Linter) checkDMARC(ctx context.Context) []Finding {
+	dmarcName := "_dmarc." + l.Zone
+	for _, r := range l.Records {
+		if strings.EqualFold(r.Type, "TXT") && strings.EqualFold(r.Name, dmarcName) {
+			txt := dnsquery.UnquoteTXT(r.Content)
+			if !strings.HasPrefix(strings.ToLower(txt), "v=dmarc1") {
+				continue
+			}
+			if strings.Contains(strings.ReplaceAll(strings.ToLower(txt), " ", ""), ";p=none") {
+				return []Finding{{Info, "dmarc", dmarcName, "TXT", "DMARC policy is p=none (monitoring only)"}}
+			}
+			return nil
+		}
+	}
+
+	severity := Info
+	if l.hasRecord(l.Zone, "MX") || l.hasSPF(l.Zone) {
+		severity = Warning
+	}
+	return []Finding{{severity, "dmarc", dmarcName, "TXT", "no DMARC record; spoofed mail from this domain is not rejected"}}
+}
+
+func (l *Linter) checkOriginExposure(ctx context.Context) []Finding {
+	proxied := map[string][]string{}
+	for _, r := range l.Records {
+		if isAddress(r.Type) && r.Proxied != nil && *r.Proxied {
+			proxied[r.Content] = append(proxied[r.Content], r.Name)
+		}
+	}
+
+	var findings []Finding
+	for _, r := range l.Records {
+		if !isAddress(r.Type) || (r.Proxied != nil && *r.Proxied) {
+			continue
+		}
+		if names, ok := proxied[r.Content]; ok {
+			findings = append(findings, Finding{Warning, "origin-exposed", r.Name, r.Type,
+				fmt.Sprintf("unproxied record exposes origin %s of proxied %s", r.Content, strings.Join(names, ", "))})
+		}
+	}
+	return findings
+}
+
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func (l *Linter) checkPrivateAddresses(ctx context.Context) []Finding {
+	var findings []Finding
+	for _, r := range l.Records {
+		if !isAddress(r.Type) {
+			continue
+		}
+		ip := net.ParseIP(r.Content)
+		if ip == nil {
+			continue
+		}
+		if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() || cgnat.Contains(ip) {
+			findings = append(findings, Finding{Warning, "private-address", r.Name, r.Type,
+				fmt.Sprintf("%s is not a public address and leaks internal network layout", r.Content)})
+		}
+	}
+	return findings
+}
+
+// hasName reports whether name exists in the zone, directly or through a
+// wildcard record.
+func (l *Linter) hasName(name string) bool {
+	wildcard := ""
+	if i := strings.Index(name, "."); i >= 0 {
+		wildcard = "*" + name[i:]
+	}
+	for _, r := range l.Records {
+		if strings.EqualFold(r.Name, name) || strings.EqualFold(r.Name, wildcard) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Linter) hasRecord(name, recordType string) bool {
+	for _, r := range l.Records {
+		if strings.EqualFold(r.Name, name) && strings.EqualFold(r.Type, recordType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Linter) hasSPF(name string) bool {
+	for _, r := range l.Records {
+		if strings.EqualFold(r.Name, name) && strings.EqualFold(r.Type, "TXT") && spf.IsSPF(dnsquery.UnquoteTXT(r.Content)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAddress(recordType string) bool {
+	return strings.EqualFold(recordType, "A") || strings.EqualFold(recordType, "AAAA")
+}
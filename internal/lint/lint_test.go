@@ -0,0 +1,71 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/dnsquery"
+)
+
+type fakeResolver map[string]*dnsquery.Answer
+
+func (f fakeResolver) Query(ctx context.Context, name, recordType string) (*dnsquery.Answer, error) {
+	if a, ok := f[recordType+" "+name]; ok {
+		return a, nil
+	}
+	return &dnsquery.Answer{Rcode: "NXDOMAIN"}, nil
+}
+
+func (f fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if a, ok := f["TXT "+name]; ok {
+		return a.Values, nil
+	}
+	return nil, fmt.Errorf("%s: NXDOMAIN", name)
+}
+
+func TestLinter(t *testing.T) {
+	yes, no := true, false
+	records := []cloudflare.DNSRecord{
+		{Type: "A", Name: "example.com", Content: "203.0.113.10", Proxied: &yes},
+		{Type: "A", Name: "direct.example.com", Content: "203.0.113.10", Proxied: &no},
+		{Type: "A", Name: "intranet.example.com", Content: "10.1.2.3"},
+		{Type: "A", Name: "dup.example.com", Content: "203.0.113.20"},
+		{Type: "A", Name: "dup.example.com", Content: "203.0.113.20"},
+		{Type: "CNAME", Name: "old.example.com", Content: "gone.example.com"},
+		{Type: "CNAME", Name: "blog.example.com", Content: "mysite.herokuapp.com"},
+		{Type: "CNAME", Name: "mailhost.example.com", Content: "mx.provider.net"},
+		{Type: "MX", Name: "example.com", Content: "mailhost.example.com"},
+		{Type: "TXT", Name: "example.com", Content: "v=spf1 mx -all"},
+		{Type: "TXT", Name: "example.com", Content: "v=spf1 a -all"},
+	}
+	resolver := fakeResolver{
+		"A mx.provider.net": {Rcode: "NOERROR", Values: []string{"198.51.100.1"}},
+	}
+
+	l := &Linter{Zone: "example.com", Records: records, Resolver: resolver}
+	got := map[string]Severity{}
+	for _, f := range l.Run(context.Background()) {
+		got[f.Check+" "+f.Name] = f.Severity
+	}
+
+	want := map[string]Severity{
+		"duplicate dup.example.com":            Warning,
+		"dangling-cname old.example.com":       Error,
+		"subdomain-takeover blog.example.com":  Error,
+		"target-is-cname example.com":          Error,
+		"spf-multiple example.com":             Error,
+		"dmarc _dmarc.example.com":             Warning,
+		"origin-exposed direct.example.com":    Warning,
+		"private-address intranet.example.com": Warning,
+	}
+	for key, severity := range want {
+		if s, ok := got[key]; !ok || s != severity {
+			t.Errorf("finding %q: got %v (present %v), want %v", key, s, ok, severity)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+}
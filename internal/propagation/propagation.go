@@ -0,0 +1,171 @@
+// Package propagation compares what nameservers answer for a record with
+// what the Cloudflare API says it should be.
+package propagation
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/dnsquery"
+)
+
+// DefaultResolvers are public recursive resolvers checked by default.
+var DefaultResolvers = []string{
+	"1.1.1.1",
+	"8.8.8.8",
+	"9.9.9.9",
+	"208.67.222.222",
+}
+
+// Role distinguishes authoritative nameservers from recursive resolvers.
+type Role string
+
+const (
+	Authoritative Role = "authoritative"
+	Resolver      Role = "resolver"
+)
+
+// Server is a nameserver to query.
+type Server struct {
+	Address string
+	Role    Role
+}
+
+// Servers builds the server list from the zone's nameservers and the
+// resolvers to check.
+func Servers(nameservers, resolvers []string) []Server {
+	servers := make([]Server, 0, len(nameservers)+len(resolvers))
+	for _, ns := range nameservers {
+		servers = append(servers, Server{Address: ns, Role: Authoritative})
+	}
+	for _, r := range resolvers {
+		servers = append(servers, Server{Address: r, Role: Resolver})
+	}
+	return servers
+}
+
+// Result is one server's answer and whether it matches the expected values.
+type Result struct {
+	Server Server
+	Answer *dnsquery.Answer
+	Err    error
+	Match  bool
+}
+
+// Check queries every server concurrently for name/recordType and compares
+// each answer with expected. An empty expected list matches an empty answer,
+// which is how deletions are checked.
+func Check(ctx context.Context, servers []Server, name, recordType string, expected []string) ([]Result, error) {
+	qtype, err := dnsquery.Type(recordType)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(servers))
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func(i int, server Server) {
+			defer wg.Done()
+			answer, err := dnsquery.Query(ctx, server.Address, name, qtype)
+			results[i] = Result{Server: server, Answer: answer, Err: err}
+			if err == nil {
+				results[i].Match = slices.Equal(answer.Values, expected)
+			}
+		}(i, server)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// MatchAuthoritative re-evaluates results against the first successful
+// authoritative answer and returns it. It is used when the expected answer
+// cannot be derived from the API.
+func MatchAuthoritative(results []Result) []string {
+	var expected []string
+	found := false
+	for _, r := range results {
+		if r.Server.Role == Authoritative && r.Err == nil {
+			expected, found = r.Answer.Values, true
+			break
+		}
+	}
+	for i := range results {
+		results[i].Match = found && results[i].Err == nil && slices.Equal(results[i].Answer.Values, expected)
+	}
+	return expected
+}
+
+// Agree reports whether every result matched.
+func Agree(results []Result) bool {
+	for _, r := range results {
+		if !r.Match {
+			return false
+		}
+	}
+	return true
+}
+
+// RecordType picks the type to check for a name from its records: CNAME
+// when there is one, since resolvers follow it, then A, AAAA or the first
+// record's type. It returns A when there are no records.
+func RecordType(records []cloudflare.DNSRecord) string {
+	for _, want := range []string{"CNAME", "A", "AAAA"} {
+		for _, r := range records {
+			if strings.EqualFold(r.Type, want) {
+				return want
+			}
+		}
+	}
+	if len(records) > 0 {
+		return strings.ToUpper(records[0].Type)
+	}
+	return "A"
+}
+
+// Expected returns the answers the records should produce, in the same
+// normalized, sorted form as dnsquery answers. It returns false when the
+// answers cannot be predicted from the API, which is the case for proxied
+// records since Cloudflare answers with its own edge addresses.
+func Expected(records []cloudflare.DNSRecord) ([]string, bool) {
+	values := make([]string, 0, len(records))
+	for _, r := range records {
+		if r.Proxied != nil && *r.Proxied {
+			return nil, false
+		}
+		values = append(values, normalizeContent(r))
+	}
+	slices.Sort(values)
+	return values, true
+}
+
+func normalizeContent(r cloudflare.DNSRecord) string {
+	switch strings.ToUpper(r.Type) {
+	case "A", "AAAA":
+		if ip := net.ParseIP(r.Content); ip != nil {
+			return ip.String()
+		}
+	case "CNAME", "NS", "PTR":
+		return dnsquery.NormalizeName(r.Content)
+	case "TXT":
+		return dnsquery.UnquoteTXT(r.Content)
+	case "MX":
+		priority := 0
+		if r.Priority != nil {
+			priority = int(*r.Priority)
+		}
+		return strconv.Itoa(priority) + " " + dnsquery.NormalizeName(r.Content)
+	case "SRV":
+		priority := 0
+		if r.Priority != nil {
+			priority = int(*r.Priority)
+		}
+		return strconv.Itoa(priority) + " " + dnsquery.NormalizeName(r.Content)
+	}
+	return r.Content
+}
@@ -0,0 +1,82 @@
+package propagation
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+)
+
+func TestExpected(t *testing.T) {
+	priority := uint16(10)
+	proxied := true
+
+	tests := []struct {
+		name    string
+		records []cloudflare.DNSRecord
+		want    []string
+		wantOK  bool
+	}{
+		{
+			name: "A records sorted",
+			records: []cloudflare.DNSRecord{
+				{Type: "A", Content: "192.0.2.2"},
+				{Type: "A", Content: "192.0.2.1"},
+			},
+			want:   []string{"192.0.2.1", "192.0.2.2"},
+			wantOK: true,
+		},
+		{
+			name:    "MX with priority",
+			records: []cloudflare.DNSRecord{{Type: "MX", Content: "Mail.Example.com.", Priority: &priority}},
+			want:    []string{"10 mail.example.com"},
+			wantOK:  true,
+		},
+		{
+			name:    "quoted TXT",
+			records: []cloudflare.DNSRecord{{Type: "TXT", Content: `"v=spf1 -all"`}},
+			want:    []string{"v=spf1 -all"},
+			wantOK:  true,
+		},
+		{
+			name:    "proxied cannot be predicted",
+			records: []cloudflare.DNSRecord{{Type: "A", Content: "192.0.2.1", Proxied: &proxied}},
+			wantOK:  false,
+		},
+		{
+			name:   "no records expects empty answer",
+			want:   []string{},
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Expected(tt.records)
+			if ok != tt.wantOK {
+				t.Fatalf("Expected() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !slices.Equal(got, tt.want) {
+				t.Errorf("Expected() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordType(t *testing.T) {
+	tests := []struct {
+		records []cloudflare.DNSRecord
+		want    string
+	}{
+		{nil, "A"},
+		{[]cloudflare.DNSRecord{{Type: "CNAME"}}, "CNAME"},
+		{[]cloudflare.DNSRecord{{Type: "AAAA"}, {Type: "A"}}, "A"},
+		{[]cloudflare.DNSRecord{{Type: "TXT"}, {Type: "AAAA"}}, "AAAA"},
+		{[]cloudflare.DNSRecord{{Type: "txt"}, {Type: "MX"}}, "TXT"},
+	}
+	for _, tt := range tests {
+		if got := RecordType(tt.records); got != tt.want {
+			t.Errorf("RecordType(%v) = %q, want %q", tt.records, got, tt.want)
+		}
+	}
+}
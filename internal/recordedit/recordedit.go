@@ -0,0 +1,323 @@
+// Package recordedit renders DNS records as an editable YAML document and
+// turns the edited document back into a validated set of changes.
+package recordedit
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+	"go.yaml.in/yaml/v3"
+)
+
+// Entry is one record in the edited document. Entries without an ID are
+// created; records whose entry is removed are deleted.
+type Entry struct {
+	ID       string  `yaml:"id,omitempty"`
+	Type     string  `yaml:"type"`
+	Name     string  `yaml:"name"`
+	Content  string  `yaml:"content"`
+	TTL      int     `yaml:"ttl"`
+	Priority *uint16 `yaml:"priority,omitempty"`
+	Proxied  *bool   `yaml:"proxied,omitempty"`
+
+	// line is the entry's line in the document, for error annotations.
+	line int
+}
+
+// errorPrefix marks the comments Annotate adds, so they can be removed
+// again before the next annotation.
+const errorPrefix = "# ERROR: "
+
+// Marshal renders records as the document presented in the editor.
+func Marshal(zone string, records []cloudflare.DNSRecord) ([]byte, error) {
+	entries := make([]Entry, len(records))
+	for i, r := range records {
+		entries[i] = fromRecord(r)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# DNS records for %s. Edit, add or remove entries, then save and exit.\n", zone)
+	buf.WriteString("# Entries without an id are created; removed entries are deleted.\n")
+	buf.WriteString("# An empty file cancels the edit. ttl 1 means automatic.\n")
+	if len(entries) == 0 {
+		buf.WriteString("[]\n")
+		return buf.Bytes(), nil
+	}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(entries); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fromRecord(r cloudflare.DNSRecord) Entry {
+	e := Entry{ID: r.ID, Type: r.Type, Name: r.Name, Content: r.Content, TTL: r.TTL, Priority: r.Priority}
+	if r.Type != "MX" && r.Type != "SRV" {
+		e.Priority = nil
+	}
+	if proxiable(r.Type) {
+		proxied := r.Proxied != nil && *r.Proxied
+		e.Proxied = &proxied
+	}
+	return e
+}
+
+// IsEmpty reports whether data holds nothing but comments and whitespace.
+func IsEmpty(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse reads the edited document. Names are taken relative to zone unless
+// they already end in it or in a dot.
+func Parse(data []byte, zone string) ([]Entry, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	list := doc.Content[0]
+	if list.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("line %d: expected a list of records", list.Line)
+	}
+
+	entries := make([]Entry, len(list.Content))
+	for i, item := range list.Content {
+		var e Entry
+		if err := item.Decode(&e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", item.Line, err)
+		}
+		e.Type = strings.ToUpper(strings.TrimSpace(e.Type))
+		if strings.TrimSpace(e.Name) != "" {
+			e.Name = cloudflare.NormalizeName(e.Name, zone)
+		}
+		e.line = item.Line
+		// Omitted fields take the values the API would use.
+		if e.TTL == 0 {
+			e.TTL = 1
+		}
+		if e.Proxied == nil && proxiable(e.Type) {
+			proxied := false
+			e.Proxied = &proxied
+		}
+		entries[i] = e
+	}
+	return entries, nil
+}
+
+// Problem is a validation error for the entry at Line.
+type Problem struct {
+	Line    int
+	Message string
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// Validate checks the entries against the records they were created from.
+func Validate(entries []Entry, original []cloudflare.DNSRecord) []Problem {
+	known := map[string]bool{}
+	for _, r := range original {
+		known[r.ID] = true
+	}
+
+	var problems []Problem
+	seen := map[string]bool{}
+	for _, e := range entries {
+		add := func(format string, args ...any) {
+			problems = append(problems, Problem{e.line, fmt.Sprintf(format, args...)})
+		}
+		if e.ID != "" {
+			if !known[e.ID] {
+				add("unknown id %q (remove it to create a new record)", e.ID)
+			}
+			if seen[e.ID] {
+				add("id %q appears more than once", e.ID)
+			}
+			seen[e.ID] = true
+		}
+		if e.Type == "" {
+			add("type is required")
+		}
+		if e.Name == "" {
+			add("name is required")
+		}
+		if strings.TrimSpace(e.Content) == "" {
+			add("content is required")
+		}
+		if e.TTL != 1 && (e.TTL < 60 || e.TTL > 86400) {
+			add("ttl %d is invalid (1 for auto, or 60-86400)", e.TTL)
+		}
+		if (e.Type == "MX" || e.Type == "SRV") && e.Priority == nil {
+			add("%s records need a priority", e.Type)
+		}
+		if e.Proxied != nil && *e.Proxied && !proxiable(e.Type) {
+			add("%s records cannot be proxied", e.Type)
+		}
+	}
+	return problems
+}
+
+// Annotate returns data with the problems inserted as comments above the
+// entries they refer to and summarized at the top. Annotations from an
+// earlier round are removed first.
+func Annotate(data []byte, summary string, problems []Problem) []byte {
+	byLine := map[int][]string{}
+	for _, p := range problems {
+		byLine[p.Line] = append(byLine[p.Line], p.Message)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(errorPrefix + summary + "\n")
+	for _, p := range problems {
+		out.WriteString(errorPrefix + p.Error() + "\n")
+	}
+
+	// Line numbers refer to data before stripping old annotations.
+	for i, line := range strings.SplitAfter(string(data), "\n") {
+		for _, msg := range byLine[i+1] {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+			out.WriteString(indent + errorPrefix + msg + "\n")
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), errorPrefix) {
+			continue
+		}
+		out.WriteString(line)
+	}
+	return out.Bytes()
+}
+
+// Change is an update of an existing record.
+type Change struct {
+	Old cloudflare.DNSRecord
+	New Entry
+}
+
+// Plan is the set of API calls needed to apply an edit.
+type Plan struct {
+	Create []Entry
+	Update []Change
+	Delete []cloudflare.DNSRecord
+}
+
+// Empty reports whether the plan makes no changes.
+func (p *Plan) Empty() bool {
+	return len(p.Create) == 0 && len(p.Update) == 0 && len(p.Delete) == 0
+}
+
+// Diff compares the edited entries to the original records.
+func Diff(original []cloudflare.DNSRecord, entries []Entry) *Plan {
+	plan := &Plan{}
+	kept := map[string]bool{}
+	byID := map[string]cloudflare.DNSRecord{}
+	for _, r := range original {
+		byID[r.ID] = r
+	}
+	for _, e := range entries {
+		if e.ID == "" {
+			plan.Create = append(plan.Create, e)
+			continue
+		}
+		kept[e.ID] = true
+		old := byID[e.ID]
+		if !sameEntry(fromRecord(old), e) {
+			plan.Update = append(plan.Update, Change{old, e})
+		}
+	}
+	for _, r := range original {
+		if !kept[r.ID] {
+			plan.Delete = append(plan.Delete, r)
+		}
+	}
+	return plan
+}
+
+// Action is one API call needed to apply a plan.
+type Action struct {
+	// Kind is "delete", "update" or "create".
+	Kind string
+	// Record is the record deleted or updated.
+	Record cloudflare.DNSRecord
+	// Entry holds the created record or the new values of an update.
+	Entry Entry
+}
+
+// Actions returns the plan's changes in the order they are applied: deletes
+// first, then updates, then creates. A name can then be taken over by a
+// record of another type, such as a CNAME replacing an A record, since
+// the API rejects a CNAME next to any other record at the same name.
+func (p *Plan) Actions() []Action {
+	var actions []Action
+	for _, r := range p.Delete {
+		actions = append(actions, Action{Kind: "delete", Record: r})
+	}
+	for _, c := range p.Update {
+		actions = append(actions, Action{Kind: "update", Record: c.Old, Entry: c.New})
+	}
+	for _, e := range p.Create {
+		actions = append(actions, Action{Kind: "create", Entry: e})
+	}
+	return actions
+}
+
+func sameEntry(a, b Entry) bool {
+	return strings.EqualFold(a.Type, b.Type) && strings.EqualFold(a.Name, b.Name) &&
+		a.Content == b.Content && a.TTL == b.TTL &&
+		equalPtr(a.Priority, b.Priority) && equalPtr(a.Proxied, b.Proxied)
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Describe lists the differences between the old record and the edited
+// entry, e.g. "content: 192.0.2.1 → 192.0.2.2".
+func (c Change) Describe() []string {
+	old := fromRecord(c.Old)
+	var diffs []string
+	field := func(name, from, to string) {
+		if from != to {
+			diffs = append(diffs, fmt.Sprintf("%s: %s → %s", name, from, to))
+		}
+	}
+	field("type", old.Type, c.New.Type)
+	field("name", old.Name, c.New.Name)
+	field("content", old.Content, c.New.Content)
+	field("ttl", strconv.Itoa(old.TTL), strconv.Itoa(c.New.TTL))
+	field("priority", ptrString(old.Priority), ptrString(c.New.Priority))
+	field("proxied", ptrString(old.Proxied), ptrString(c.New.Proxied))
+	return diffs
+}
+
+func ptrString[T any](p *T) string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprint(*p)
+}
+
+func proxiable(t string) bool {
+	switch strings.ToUpper(t) {
+	case "A", "AAAA", "CNAME":
+		return true
+	}
+	return false
+}
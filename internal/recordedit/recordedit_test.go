@@ -0,0 +1,125 @@
+package recordedit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var original = []cloudflare.DNSRecord{
+	{ID: "1", Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 1, Proxied: ptr(true)},
+	{ID: "2", Type: "MX", Name: "example.com", Content: "mx.example.net", TTL: 3600, Priority: ptr(uint16(10))},
+	{ID: "3", Type: "TXT", Name: "example.com", Content: "hello", TTL: 300},
+}
+
+func TestRoundTripIsUnchanged(t *testing.T) {
+	data, err := Marshal("example.com", original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := Parse(data, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := Validate(entries, original); len(problems) != 0 {
+		t.Fatalf("Validate() = %v", problems)
+	}
+	if plan := Diff(original, entries); !plan.Empty() {
+		t.Errorf("Diff() = %+v, want no changes", plan)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	data, _ := Marshal("example.com", original)
+	edited := strings.Replace(string(data), "192.0.2.1", "192.0.2.2", 1)
+	edited = strings.Replace(edited, "content: hello", "content: bye", 1)
+	edited += "- type: CNAME\n  name: blog.example.com\n  content: example.github.io\n  ttl: 1\n"
+
+	entries, err := Parse([]byte(edited), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Drop the MX record.
+	var kept []Entry
+	for _, e := range entries {
+		if e.Type != "MX" {
+			kept = append(kept, e)
+		}
+	}
+
+	plan := Diff(original, kept)
+	if len(plan.Create) != 1 || len(plan.Update) != 2 || len(plan.Delete) != 1 {
+		t.Fatalf("plan has %d creates, %d updates, %d deletes", len(plan.Create), len(plan.Update), len(plan.Delete))
+	}
+	if got := plan.Update[0].Describe(); len(got) != 1 || got[0] != "content: 192.0.2.1 → 192.0.2.2" {
+		t.Errorf("Describe() = %q", got)
+	}
+	if plan.Delete[0].ID != "2" {
+		t.Errorf("deleted %s, want 2", plan.Delete[0].ID)
+	}
+}
+
+func TestActionsSwapTypeAtSameName(t *testing.T) {
+	// Replace the www A record with a CNAME at the same name.
+	data := []byte("- type: CNAME\n  name: www.example.com\n  content: example.github.io\n  ttl: 1\n" +
+		"- id: 2\n  type: MX\n  name: example.com\n  content: mx.example.net\n  ttl: 3600\n  priority: 10\n" +
+		"- id: 3\n  type: TXT\n  name: example.com\n  content: hello\n  ttl: 300\n")
+	entries, err := Parse(data, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := Validate(entries, original); len(problems) != 0 {
+		t.Fatalf("Validate() = %v", problems)
+	}
+
+	actions := Diff(original, entries).Actions()
+	var got []string
+	for _, a := range actions {
+		got = append(got, a.Kind+" "+a.Record.Type+a.Entry.Type)
+	}
+	if want := "delete A,create CNAME"; strings.Join(got, ",") != want {
+		t.Errorf("Actions() = %q, want %q", strings.Join(got, ","), want)
+	}
+}
+
+func TestValidateAndAnnotate(t *testing.T) {
+	data := []byte("- id: 1\n  type: A\n  name: www.example.com\n  content: 192.0.2.1\n  ttl: 5\n" +
+		"- id: 9\n  type: TXT\n  name: example.com\n  content: x\n  ttl: 1\n  proxied: true\n")
+	entries, err := Parse(data, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems := Validate(entries, original)
+	if len(problems) != 3 {
+		t.Fatalf("Validate() = %v, want 3 problems", problems)
+	}
+
+	annotated := Annotate(data, "2 records have problems", problems)
+	if !strings.Contains(string(annotated), "# ERROR: ttl 5 is invalid (1 for auto, or 60-86400)\n- id: 1\n") {
+		t.Errorf("problem not placed above its entry:\n%s", annotated)
+	}
+
+	// Annotating again replaces the earlier comments instead of piling up.
+	again, err := Parse(annotated, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reannotated := Annotate(annotated, "2 records have problems", Validate(again, original))
+	if n := strings.Count(string(reannotated), "ttl 5 is invalid"); n != 2 {
+		t.Errorf("ttl problem appears %d times, want 2 (summary and entry):\n%s", n, reannotated)
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	entries, err := Parse([]byte("- id: 1\n  type: a\n  name: www\n  content: 192.0.2.1\n"), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := entries[0]
+	if e.Type != "A" || e.Name != "www.example.com" || e.TTL != 1 || e.Proxied == nil || *e.Proxied {
+		t.Errorf("Parse() = %+v", e)
+	}
+}
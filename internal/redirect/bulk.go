@@ -0,0 +1,215 @@
+package redirect
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+)
+
+// CSVColumns are the columns of a bulk redirect CSV file, in the order
+// used when the file has no header row. This is the order the dashboard's
+// CSV upload uses, plus a trailing comment.
+var CSVColumns = []string{
+	"source_url", "target_url", "status_code",
+	"include_subdomains", "subpath_matching", "preserve_query_string", "preserve_path_suffix",
+	"comment",
+}
+
+var bulkStatusCodes = map[int]bool{301: true, 302: true, 307: true, 308: true}
+
+// RowError is a problem with one row of a CSV file.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ReadCSV reads redirect list items from a CSV file. The file may start
+// with a header row naming its columns (see CSVColumns); otherwise the
+// columns are taken in CSVColumns order and trailing ones may be left out.
+// Lines starting with # are skipped. Rows that fail validation are
+// returned as RowErrors alongside the valid items.
+func ReadCSV(r io.Reader) ([]cloudflare.ListItem, []RowError) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+
+	var items []cloudflare.ListItem
+	var errs []RowError
+	columns := CSVColumns
+	seen := map[string]int{}
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// FieldPos is only valid after a successful Read.
+			var line int
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				line = parseErr.StartLine
+				err = parseErr.Err
+			}
+			errs = append(errs, RowError{Line: line, Err: err})
+			continue
+		}
+		line, _ := cr.FieldPos(0)
+		if first && isCSVColumn(record[0]) {
+			columns, err = csvHeader(record)
+			if err != nil {
+				return nil, []RowError{{Line: line, Err: err}}
+			}
+			continue
+		}
+
+		item, err := parseCSVRow(columns, record)
+		if err != nil {
+			errs = append(errs, RowError{Line: line, Err: err})
+			continue
+		}
+		if prev, ok := seen[item.Key()]; ok {
+			errs = append(errs, RowError{Line: line, Err: fmt.Errorf("duplicate source URL %s (first on line %d)", item.Key(), prev)})
+			continue
+		}
+		seen[item.Key()] = line
+		items = append(items, item)
+	}
+	return items, errs
+}
+
+func isCSVColumn(name string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, c := range CSVColumns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func csvHeader(record []string) ([]string, error) {
+	columns := make([]string, len(record))
+	for i, name := range record {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isCSVColumn(name) {
+			return nil, fmt.Errorf("unknown column %q (use %s)", name, strings.Join(CSVColumns, ", "))
+		}
+		columns[i] = name
+	}
+	return columns, nil
+}
+
+func parseCSVRow(columns, record []string) (cloudflare.ListItem, error) {
+	if len(record) > len(columns) {
+		return cloudflare.ListItem{}, fmt.Errorf("%d fields, expected at most %d", len(record), len(columns))
+	}
+	fields := map[string]string{}
+	for i, v := range record {
+		fields[columns[i]] = strings.TrimSpace(v)
+	}
+
+	r := &cloudflare.ListRedirect{
+		SourceURL:  fields["source_url"],
+		TargetURL:  fields["target_url"],
+		StatusCode: DefaultStatus,
+	}
+	if err := validateBulkSource(r.SourceURL); err != nil {
+		return cloudflare.ListItem{}, err
+	}
+	if u, err := url.Parse(r.TargetURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return cloudflare.ListItem{}, fmt.Errorf("invalid target_url %q (use an absolute http:// or https:// URL)", r.TargetURL)
+	}
+	if s := fields["status_code"]; s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || !bulkStatusCodes[n] {
+			return cloudflare.ListItem{}, fmt.Errorf("invalid status_code %q (use 301, 302, 307 or 308)", s)
+		}
+		r.StatusCode = n
+	}
+	for _, f := range []struct {
+		column string
+		dst    *bool
+	}{
+		{"include_subdomains", &r.IncludeSubdomains},
+		{"subpath_matching", &r.SubpathMatching},
+		{"preserve_query_string", &r.PreserveQueryString},
+		{"preserve_path_suffix", &r.PreservePathSuffix},
+	} {
+		b, err := parseCSVBool(fields[f.column])
+		if err != nil {
+			return cloudflare.ListItem{}, fmt.Errorf("%s: %w", f.column, err)
+		}
+		*f.dst = b
+	}
+	if r.PreservePathSuffix && !r.SubpathMatching {
+		return cloudflare.ListItem{}, fmt.Errorf("preserve_path_suffix requires subpath_matching")
+	}
+	return cloudflare.ListItem{Redirect: r, Comment: fields["comment"]}, nil
+}
+
+// validateBulkSource checks a source URL: a host and optional path, with an
+// optional http:// or https:// scheme, and no query string or fragment.
+func validateBulkSource(source string) error {
+	if source == "" {
+		return fmt.Errorf("source_url is required")
+	}
+	rest := source
+	if scheme, after, ok := strings.Cut(source, "://"); ok {
+		if scheme != "http" && scheme != "https" {
+			return fmt.Errorf("invalid source_url %q: scheme must be http or https", source)
+		}
+		rest = after
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	if host == "" || strings.ContainsAny(source, "?# \t") {
+		return fmt.Errorf("invalid source_url %q (use a host and path without query string or fragment)", source)
+	}
+	return nil
+}
+
+func parseCSVBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "", "false", "no", "off", "0":
+		return false, nil
+	case "true", "yes", "on", "1":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+// WriteCSV writes the redirect items as a CSV file with a header row, in
+// the format ReadCSV reads. Items without a redirect are skipped.
+func WriteCSV(w io.Writer, items []cloudflare.ListItem) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(CSVColumns); err != nil {
+		return err
+	}
+	for _, item := range items {
+		r := item.Redirect
+		if r == nil {
+			continue
+		}
+		err := cw.Write([]string{
+			r.SourceURL, r.TargetURL, strconv.Itoa(r.StatusCode),
+			strconv.FormatBool(r.IncludeSubdomains), strconv.FormatBool(r.SubpathMatching),
+			strconv.FormatBool(r.PreserveQueryString), strconv.FormatBool(r.PreservePathSuffix),
+			item.Comment,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
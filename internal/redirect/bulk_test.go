@@ -0,0 +1,76 @@
+package redirect
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+)
+
+func TestReadCSV(t *testing.T) {
+	in := `# legacy URLs
+example.com/old,https://example.com/new
+example.com/blog,https://blog.example.com,308,true,true,false,true,moved blog
+example.com/bad,/relative
+example.com/old,https://example.com/other
+example.com/x?y=1,https://example.com/
+example.com/a,https://example.com/b,200
+example.com/c,https://example.com/d,301,false,false,false,true
+example.com/a"b,https://example.org/
+example.com/e,https://example.com/f
+`
+	items, errs := ReadCSV(strings.NewReader(in))
+	want := []cloudflare.ListItem{
+		{Redirect: &cloudflare.ListRedirect{SourceURL: "example.com/old", TargetURL: "https://example.com/new", StatusCode: 301}},
+		{Redirect: &cloudflare.ListRedirect{
+			SourceURL: "example.com/blog", TargetURL: "https://blog.example.com", StatusCode: 308,
+			IncludeSubdomains: true, SubpathMatching: true, PreservePathSuffix: true,
+		}, Comment: "moved blog"},
+		{Redirect: &cloudflare.ListRedirect{SourceURL: "example.com/e", TargetURL: "https://example.com/f", StatusCode: 301}},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+	var lines []int
+	for _, e := range errs {
+		lines = append(lines, e.Line)
+	}
+	if want := []int{4, 5, 6, 7, 8, 9}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("error lines = %v, want %v (%v)", lines, want, errs)
+	}
+}
+
+func TestReadCSVHeader(t *testing.T) {
+	in := "target_url,source_url,comment\nhttps://example.com/new,example.com/old,hi\n"
+	items, errs := ReadCSV(strings.NewReader(in))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if len(items) != 1 || items[0].Redirect.SourceURL != "example.com/old" || items[0].Comment != "hi" {
+		t.Errorf("items = %+v", items)
+	}
+
+	if _, errs := ReadCSV(strings.NewReader("source_url,target,comment\n")); len(errs) != 1 {
+		t.Errorf("unknown column: errs = %v, want one", errs)
+	}
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	items := []cloudflare.ListItem{
+		{Redirect: &cloudflare.ListRedirect{SourceURL: "example.com/old", TargetURL: "https://example.com/new", StatusCode: 302, PreserveQueryString: true}, Comment: "a, b"},
+		{IP: "192.0.2.1"},
+	}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, items); err != nil {
+		t.Fatal(err)
+	}
+	got, errs := ReadCSV(&buf)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if !reflect.DeepEqual(got, items[:1]) {
+		t.Errorf("round trip = %+v, want %+v", got, items[:1])
+	}
+}
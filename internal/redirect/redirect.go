@@ -0,0 +1,208 @@
+// Package redirect turns "from → to" redirect specs into Cloudflare rule
+// expressions, and converts forwarding page rules into such specs.
+package redirect
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultStatus is the status code used when a spec gives none.
+const DefaultStatus = 301
+
+// Spec is a single redirect: requests matching From are redirected to To.
+//
+// From is a host and optional path, e.g. "example.com/old" or
+// "*.example.com/blog/*", optionally with an http:// or https:// scheme to
+// match that scheme only. A * matches any run of characters. To is an
+// absolute URL; ${1}, ${2}, ... in its path are replaced by what the *s in
+// From's path matched.
+type Spec struct {
+	From          string
+	To            string
+	Status        int
+	PreserveQuery bool
+}
+
+var statusCodes = map[int]bool{301: true, 302: true, 303: true, 307: true, 308: true}
+
+// ParseSpec parses "from → to [status]". "->" works as well as "→", and
+// the arrow may be left out.
+func ParseSpec(s string) (Spec, error) {
+	s = strings.ReplaceAll(s, "→", " ")
+	s = strings.ReplaceAll(s, "->", " ")
+	fields := strings.Fields(s)
+	if len(fields) < 2 || len(fields) > 3 {
+		return Spec{}, fmt.Errorf("invalid redirect %q (use: from → to [status])", s)
+	}
+	spec := Spec{From: fields[0], To: fields[1], Status: DefaultStatus}
+	if len(fields) == 3 {
+		n, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return Spec{}, fmt.Errorf("invalid status code %q", fields[2])
+		}
+		spec.Status = n
+	}
+	return spec, spec.Validate()
+}
+
+// String renders the spec in the form ParseSpec reads.
+func (s Spec) String() string {
+	return fmt.Sprintf("%s → %s %d", s.From, s.To, s.Status)
+}
+
+// Validate checks the spec.
+func (s Spec) Validate() error {
+	if !statusCodes[s.Status] {
+		return fmt.Errorf("invalid status code %d (use 301, 302, 303, 307 or 308)", s.Status)
+	}
+	src, err := parseSource(s.From)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(s.To)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid target %q (use an absolute http:// or https:// URL)", s.To)
+	}
+	if len(references(u.Scheme+"://"+u.Host)) > 0 {
+		return fmt.Errorf("target %q: wildcards can only be used in the path", s.To)
+	}
+	for _, n := range references(s.To) {
+		if n < 1 || n > strings.Count(src.path, "*") {
+			return fmt.Errorf("target %q uses ${%d} but the path of %q has %d wildcard(s)", s.To, n, s.From, strings.Count(src.path, "*"))
+		}
+	}
+	return nil
+}
+
+// source is a parsed From.
+type source struct {
+	scheme string // "", "http" or "https"
+	host   string
+	path   string
+}
+
+func parseSource(from string) (source, error) {
+	var src source
+	rest := from
+	if scheme, after, ok := strings.Cut(rest, "://"); ok {
+		src.scheme = strings.ToLower(scheme)
+		if src.scheme != "http" && src.scheme != "https" {
+			return source{}, fmt.Errorf("invalid source %q: scheme must be http or https", from)
+		}
+		rest = after
+	}
+	host, path, _ := strings.Cut(rest, "/")
+	src.host = strings.ToLower(host)
+	src.path = "/" + path
+	if src.host == "" {
+		return source{}, fmt.Errorf("invalid source %q: host is required", from)
+	}
+	if strings.ContainsAny(from, `"?#`) || strings.ContainsAny(src.host, ":") {
+		return source{}, fmt.Errorf("invalid source %q: use a host and path without port, query or fragment", from)
+	}
+	return src, nil
+}
+
+// Expression returns the rule expression matching requests to From.
+func (s Spec) Expression() (string, error) {
+	src, err := parseSource(s.From)
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+	if strings.Contains(src.host, "*") {
+		parts = append(parts, "http.host wildcard "+quote(src.host))
+	} else {
+		parts = append(parts, "http.host eq "+quote(src.host))
+	}
+	if strings.Contains(src.path, "*") {
+		parts = append(parts, "http.request.uri.path wildcard "+quote(src.path))
+	} else {
+		parts = append(parts, "http.request.uri.path eq "+quote(src.path))
+	}
+	switch src.scheme {
+	case "https":
+		parts = append(parts, "ssl")
+	case "http":
+		parts = append(parts, "not ssl")
+	}
+	return "(" + strings.Join(parts, " and ") + ")", nil
+}
+
+// Target returns the redirect target: a static URL, or, when To refers to
+// wildcards in From, an expression computing it.
+func (s Spec) Target() (value, expression string, err error) {
+	if len(references(s.To)) == 0 {
+		return s.To, "", nil
+	}
+	src, err := parseSource(s.From)
+	if err != nil {
+		return "", "", err
+	}
+	u, err := url.Parse(s.To)
+	if err != nil {
+		return "", "", err
+	}
+	origin := u.Scheme + "://" + u.Host
+	path := strings.TrimPrefix(s.To, origin)
+	if path == "" {
+		path = "/"
+	}
+	return "", fmt.Sprintf("concat(%s, wildcard_replace(http.request.uri.path, %s, %s))", quote(origin), quote(src.path), quote(path)), nil
+}
+
+// quote renders s as a string literal of the rules language, which only
+// escapes quotes and backslashes.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+var referenceRE = regexp.MustCompile(`\$\{(\d+)\}`)
+
+// references returns the wildcard numbers ${n} used in to.
+func references(to string) []int {
+	var refs []int
+	for _, m := range referenceRE.FindAllStringSubmatch(to, -1) {
+		n, _ := strconv.Atoi(m[1])
+		refs = append(refs, n)
+	}
+	return refs
+}
+
+var pageRuleRefRE = regexp.MustCompile(`\$(\d+)`)
+
+// FromPageRule converts a forwarding page rule, given its URL pattern and
+// forwarding URL, into a spec. Page rules number all wildcards in the
+// pattern ($1, $2, ...); specs only number those in the path, so the
+// references are renumbered. Rules whose target uses a wildcard from the
+// host cannot be converted.
+func FromPageRule(pattern, forwardURL string, status int) (Spec, error) {
+	src, err := parseSource(pattern)
+	if err != nil {
+		return Spec{}, err
+	}
+	hostStars := strings.Count(src.host, "*")
+
+	var convErr error
+	to := pageRuleRefRE.ReplaceAllStringFunc(forwardURL, func(ref string) string {
+		n, _ := strconv.Atoi(ref[1:])
+		if n <= hostStars {
+			convErr = fmt.Errorf("target %q uses $%d, which is part of the host in %q", forwardURL, n, pattern)
+			return ref
+		}
+		return fmt.Sprintf("${%d}", n-hostStars)
+	})
+	if convErr != nil {
+		return Spec{}, convErr
+	}
+
+	if status == 0 {
+		status = DefaultStatus
+	}
+	spec := Spec{From: pattern, To: to, Status: status, PreserveQuery: true}
+	return spec, spec.Validate()
+}
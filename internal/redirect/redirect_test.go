@@ -0,0 +1,95 @@
+package redirect
+
+import "testing"
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Spec
+		wantErr bool
+	}{
+		{"example.com/old → https://example.com/new", Spec{From: "example.com/old", To: "https://example.com/new", Status: 301}, false},
+		{"example.com/old -> https://example.com/new 302", Spec{From: "example.com/old", To: "https://example.com/new", Status: 302}, false},
+		{"example.com/blog/* https://blog.example.com/${1} 308", Spec{From: "example.com/blog/*", To: "https://blog.example.com/${1}", Status: 308}, false},
+		{"example.com/old → https://example.com/new 200", Spec{}, true},
+		{"example.com/old → /new", Spec{}, true},
+		{"example.com/old → https://example.com/${1}", Spec{}, true},
+		{"example.com/old?x=1 → https://example.com/", Spec{}, true},
+		{"ftp://example.com/ → https://example.com/", Spec{}, true},
+		{"example.com/old", Spec{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpec(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSpec(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("ParseSpec(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExpressionAndTarget(t *testing.T) {
+	tests := []struct {
+		spec       Spec
+		expr       string
+		value      string
+		targetExpr string
+	}{
+		{
+			Spec{From: "Example.com/old", To: "https://example.com/new", Status: 301},
+			`(http.host eq "example.com" and http.request.uri.path eq "/old")`,
+			"https://example.com/new", "",
+		},
+		{
+			Spec{From: "https://*.example.com", To: "https://example.com/", Status: 301},
+			`(http.host wildcard "*.example.com" and http.request.uri.path eq "/" and ssl)`,
+			"https://example.com/", "",
+		},
+		{
+			Spec{From: "example.com/blog/*", To: "https://blog.example.com/posts/${1}", Status: 301},
+			`(http.host eq "example.com" and http.request.uri.path wildcard "/blog/*")`,
+			"", `concat("https://blog.example.com", wildcard_replace(http.request.uri.path, "/blog/*", "/posts/${1}"))`,
+		},
+	}
+	for _, tt := range tests {
+		expr, err := tt.spec.Expression()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expr != tt.expr {
+			t.Errorf("%s: expression\ngot  %s\nwant %s", tt.spec, expr, tt.expr)
+		}
+		value, targetExpr, err := tt.spec.Target()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != tt.value || targetExpr != tt.targetExpr {
+			t.Errorf("%s: target = %q, %q; want %q, %q", tt.spec, value, targetExpr, tt.value, tt.targetExpr)
+		}
+	}
+}
+
+func TestFromPageRule(t *testing.T) {
+	tests := []struct {
+		pattern, url string
+		wantTo       string
+		wantErr      bool
+	}{
+		{"example.com/old", "https://example.com/new", "https://example.com/new", false},
+		{"*example.com/docs/*", "https://docs.example.com/$2", "https://docs.example.com/${1}", false},
+		{"http://example.com/*/*", "https://example.com/$2/$1", "https://example.com/${2}/${1}", false},
+		{"*example.com/*", "https://$1example.org/$2", "", true},
+	}
+	for _, tt := range tests {
+		spec, err := FromPageRule(tt.pattern, tt.url, 301)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("FromPageRule(%q, %q) error = %v, wantErr %v", tt.pattern, tt.url, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (spec.To != tt.wantTo || !spec.PreserveQuery) {
+			t.Errorf("FromPageRule(%q, %q) = %+v, want To %q", tt.pattern, tt.url, spec, tt.wantTo)
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package spf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxResponseSize is the largest DNS response guaranteed to fit in a UDP
+// packet without EDNS. Flattened records are kept within it so receivers
+// never need to retry over TCP.
+const MaxResponseSize = 512
+
+// maxQueries bounds the DNS queries made while flattening one policy.
+const maxQueries = 200
+
+// Resolver provides the lookups needed to flatten a policy.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupIP(ctx context.Context, name string) ([]string, error)
+	LookupMX(ctx context.Context, name string) ([]string, error)
+}
+
+// Flattened is a policy with include, a, mx and redirect resolved to
+// address ranges.
+type Flattened struct {
+	// Networks are the ranges that pass, de-duplicated and sorted with IPv4
+	// first.
+	Networks []netip.Prefix
+	// Kept holds terms that cannot be flattened (ptr, exists, macros, and
+	// mechanisms with a qualifier other than pass) in their original order.
+	Kept []Term
+	// KeptBefore is the number of Kept terms that came before the first
+	// flattened term; the ranges go between them and the rest.
+	KeptBefore int
+	// All is the policy's final "all" mechanism, if any.
+	All *Term
+	// Modifiers holds the policy's modifiers other than redirect, such as
+	// exp and unknown ones, in their original order.
+	Modifiers []Term
+}
+
+// Part is one TXT record of a flattened policy.
+type Part struct {
+	Name    string
+	Content string
+}
+
+var errNotFlattenable = errors.New("cannot be flattened")
+
+type flattener struct {
+	lookup  Resolver
+	queries int
+	nets    map[netip.Prefix]bool
+	// added lists the ranges in the order they were found.
+	added []netip.Prefix
+}
+
+// Flatten resolves r, the policy published at domain, into address ranges.
+// Includes of policies that use ptr, exists or macros are kept as they are.
+//
+// Only pass mechanisms of included policies are collected: a nested fail
+// or softfail only makes the include not match, so it has no effect on the
+// outer policy unless it shadows a later pass of the same included policy.
+//
+// All ranges end up where the first flattened term was. Flattening fails
+// when that moves a range ahead of a kept term that is not a pass and may
+// match the same addresses, since SPF uses the first match.
+func Flatten(ctx context.Context, r *Record, domain string, lookup Resolver) (*Flattened, error) {
+	f := &flattener{lookup: lookup, nets: map[netip.Prefix]bool{}}
+	out := &Flattened{}
+
+	var redirect *Term
+	flattened := false
+	for _, t := range r.Terms {
+		switch {
+		case t.Modifier && t.Name == "redirect":
+			t := t
+			redirect = &t
+			continue
+		case t.Modifier:
+			out.Modifiers = append(out.Modifiers, t)
+			continue
+		case t.Name == "all":
+			t := t
+			out.All = &t
+			continue
+		case t.Qualifier != 0 && t.Qualifier != '+':
+			out.Kept = append(out.Kept, t)
+			continue
+		}
+
+		start := len(f.added)
+		err := f.term(ctx, t, domain, map[string]bool{domain: true}, 0)
+		if errors.Is(err, errNotFlattenable) {
+			out.Kept = append(out.Kept, t)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !flattened {
+			out.KeptBefore = len(out.Kept)
+			flattened = true
+		} else if err := checkShadowed(t, f.added[start:], out.Kept[out.KeptBefore:]); err != nil {
+			return nil, err
+		}
+	}
+
+	// A redirect only applies when the policy has no "all" of its own; the
+	// redirected policy then supplies the final result too.
+	if redirect != nil && out.All == nil {
+		target, err := f.fetch(ctx, redirect.Value)
+		if err != nil {
+			return nil, err
+		}
+		nested, err := Flatten(ctx, target, redirect.Value, lookup)
+		if err != nil {
+			return nil, err
+		}
+		if !flattened {
+			out.KeptBefore = len(out.Kept) + nested.KeptBefore
+			flattened = true
+		} else {
+			// The redirected ranges move ahead of the rest of this policy and
+			// of the redirected policy's leading kept terms.
+			between := append(slices.Clone(out.Kept[out.KeptBefore:]), nested.Kept[:nested.KeptBefore]...)
+			if err := checkShadowed(*redirect, nested.Networks, between); err != nil {
+				return nil, err
+			}
+		}
+		for _, n := range nested.Networks {
+			f.nets[n] = true
+		}
+		out.Kept = append(out.Kept, nested.Kept...)
+		out.All = nested.All
+		// The redirected policy's explanation is the one that applies.
+		for _, m := range nested.Modifiers {
+			out.Modifiers = slices.DeleteFunc(out.Modifiers, func(t Term) bool { return t.Name == m.Name })
+			out.Modifiers = append(out.Modifiers, m)
+		}
+	}
+	if !flattened {
+		out.KeptBefore = len(out.Kept)
+	}
+
+	out.Networks = collapse(f.nets)
+	return out, nil
+}
+
+// checkShadowed returns an error if moving the ranges found for t ahead of
+// the kept terms could change the result: a kept term that is not a pass
+// and matches one of the addresses would no longer be reached for it.
+// Terms other than ip4 and ip6 are assumed to match anything.
+func checkShadowed(t Term, nets []netip.Prefix, kept []Term) error {
+	if len(nets) == 0 {
+		return nil
+	}
+	for _, k := range kept {
+		if k.Qualifier == 0 || k.Qualifier == '+' {
+			continue
+		}
+		if k.Name == "ip4" || k.Name == "ip6" {
+			kp, err := parseNetwork(k.Value)
+			if err == nil && !slices.ContainsFunc(nets, kp.Overlaps) {
+				continue
+			}
+		}
+		return fmt.Errorf("cannot flatten: %s comes after %s, and flattening would move its ranges ahead of it", t, k)
+	}
+	return nil
+}
+
+func (f *flattener) term(ctx context.Context, t Term, domain string, path map[string]bool, depth int) error {
+	if strings.Contains(t.Value, "%") {
+		return fmt.Errorf("%s: macro in %s: %w", domain, t, errNotFlattenable)
+	}
+	switch t.Name {
+	case "ip4", "ip6":
+		p, err := parseNetwork(t.Value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", domain, err)
+		}
+		f.add(p)
+	case "a", "mx":
+		target, v4, v6, err := splitCIDR(t.Value, domain)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", domain, t, err)
+		}
+		hosts := []string{target}
+		if t.Name == "mx" {
+			if hosts, err = f.mx(ctx, target); err != nil {
+				return err
+			}
+		}
+		for _, host := range hosts {
+			if err := f.addresses(ctx, host, v4, v6); err != nil {
+				return err
+			}
+		}
+	case "include":
+		if depth >= MaxLookups {
+			return fmt.Errorf("include chain at %s is too deep", t.Value)
+		}
+		name := strings.ToLower(t.Value)
+		if path[name] {
+			return fmt.Errorf("include loop at %s", name)
+		}
+		nested, err := f.fetch(ctx, name)
+		if err != nil {
+			return err
+		}
+		path[name] = true
+		defer delete(path, name)
+		for _, nt := range nested.Terms {
+			if nt.Modifier || nt.Name == "all" || (nt.Qualifier != 0 && nt.Qualifier != '+') {
+				continue
+			}
+			if err := f.term(ctx, nt, name, path, depth+1); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%s: %s: %w", domain, t, errNotFlattenable)
+	}
+	return nil
+}
+
+func (f *flattener) add(p netip.Prefix) {
+	f.nets[p] = true
+	f.added = append(f.added, p)
+}
+
+func (f *flattener) query() error {
+	f.queries++
+	if f.queries > maxQueries {
+		return fmt.Errorf("policy needs more than %d DNS queries to flatten", maxQueries)
+	}
+	return nil
+}
+
+func (f *flattener) fetch(ctx context.Context, domain string) (*Record, error) {
+	if err := f.query(); err != nil {
+		return nil, err
+	}
+	return Fetch(ctx, domain, f.lookup.LookupTXT)
+}
+
+func (f *flattener) mx(ctx context.Context, domain string) ([]string, error) {
+	if err := f.query(); err != nil {
+		return nil, err
+	}
+	hosts, err := f.lookup.LookupMX(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("lookup MX for %s: %w", domain, err)
+	}
+	// Receivers give up after ten MX hosts (RFC 7208 section 4.6.4).
+	if len(hosts) > 10 {
+		return nil, fmt.Errorf("%s has %d MX hosts, more than the 10 SPF allows", domain, len(hosts))
+	}
+	return hosts, nil
+}
+
+func (f *flattener) addresses(ctx context.Context, host string, v4, v6 int) error {
+	if err := f.query(); err != nil {
+		return err
+	}
+	addrs, err := f.lookup.LookupIP(ctx, host)
+	if err != nil {
+		return fmt.Errorf("lookup addresses for %s: %w", host, err)
+	}
+	for _, s := range addrs {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			continue
+		}
+		bits := v6
+		if addr.Is4() {
+			bits = v4
+		}
+		p, err := addr.Prefix(bits)
+		if err != nil {
+			return err
+		}
+		f.add(p)
+	}
+	return nil
+}
+
+// splitCIDR splits an a or mx value such as "example.com/24//64" into the
+// target domain and the IPv4 and IPv6 prefix lengths.
+func splitCIDR(value, domain string) (string, int, int, error) {
+	target, cidr, _ := strings.Cut(value, "/")
+	if target == "" {
+		target = domain
+	}
+	v4, v6 := 32, 128
+	if cidr == "" {
+		return target, v4, v6, nil
+	}
+	s4, s6, dual := strings.Cut(cidr, "//")
+	if strings.HasPrefix(cidr, "/") {
+		s4, s6, dual = "", cidr[1:], true
+	}
+	var err error
+	if s4 != "" {
+		if v4, err = strconv.Atoi(s4); err != nil || v4 < 0 || v4 > 32 {
+			return "", 0, 0, fmt.Errorf("invalid IPv4 prefix length %q", s4)
+		}
+	}
+	if dual && s6 != "" {
+		if v6, err = strconv.Atoi(s6); err != nil || v6 < 0 || v6 > 128 {
+			return "", 0, 0, fmt.Errorf("invalid IPv6 prefix length %q", s6)
+		}
+	}
+	return target, v4, v6, nil
+}
+
+func parseNetwork(value string) (netip.Prefix, error) {
+	if !strings.Contains(value, "/") {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid address %q", value)
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	p, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid network %q", value)
+	}
+	return p.Masked(), nil
+}
+
+// collapse sorts the networks and drops those contained in another.
+func collapse(nets map[netip.Prefix]bool) []netip.Prefix {
+	sorted := make([]netip.Prefix, 0, len(nets))
+	for p := range nets {
+		sorted = append(sorted, p.Masked())
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Addr().Is4() != b.Addr().Is4() {
+			return a.Addr().Is4()
+		}
+		if c := a.Addr().Compare(b.Addr()); c != 0 {
+			return c < 0
+		}
+		return a.Bits() < b.Bits()
+	})
+
+	var out []netip.Prefix
+	for _, p := range sorted {
+		if n := len(out); n > 0 && out[n-1].Bits() <= p.Bits() && out[n-1].Contains(p.Addr()) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// NetworkTerm returns the ip4 or ip6 mechanism for p, omitting the prefix
+// length for single addresses.
+func NetworkTerm(p netip.Prefix) Term {
+	name := "ip6"
+	if p.Addr().Is4() {
+		name = "ip4"
+	}
+	value := p.String()
+	if p.IsSingleIP() {
+		value = p.Addr().String()
+	}
+	return Term{Name: name, Value: value}
+}
+
+// PartName returns the name of the n-th (1-based) record of a flattened
+// policy for domain.
+func PartName(domain string, n int) string {
+	return "_spf" + strconv.Itoa(n) + "." + domain
+}
+
+// Split lays f out as the root policy for domain and a chain of records
+// _spf1.<domain>, _spf2.<domain>, ... each holding as many ranges as fit in
+// a 512-byte response and including the next one. The root includes
+// _spf1 where the first flattened term was, keeping the kept terms in
+// their original order around it; the whole chain must stay within the
+// 10-lookup limit.
+func (f *Flattened) Split(domain string) (string, []Part, error) {
+	var parts []Part
+	var current []string
+	flush := func() {
+		parts = append(parts, Part{Name: PartName(domain, len(parts)+1), Content: "v=spf1 " + strings.Join(current, " ")})
+		current = nil
+	}
+	for _, p := range f.Networks {
+		term := NetworkTerm(p).String()
+		next := PartName(domain, len(parts)+2)
+		candidate := "v=spf1 " + strings.Join(append(current, term), " ") + " include:" + next
+		if len(current) > 0 && ResponseSize(PartName(domain, len(parts)+1), candidate) > MaxResponseSize {
+			flush()
+		}
+		current = append(current, term)
+	}
+	if len(current) > 0 {
+		flush()
+	}
+	for i := range parts[:max(len(parts)-1, 0)] {
+		parts[i].Content += " include:" + parts[i+1].Name
+	}
+
+	root := []string{"v=spf1"}
+	lookups := len(parts)
+	for i, t := range f.Kept {
+		if i == f.KeptBefore && len(parts) > 0 {
+			root = append(root, "include:"+parts[0].Name)
+		}
+		root = append(root, t.String())
+		if t.CausesLookup() {
+			lookups++
+		}
+	}
+	if f.KeptBefore >= len(f.Kept) && len(parts) > 0 {
+		root = append(root, "include:"+parts[0].Name)
+	}
+	if f.All != nil {
+		root = append(root, f.All.String())
+	}
+	for _, m := range f.Modifiers {
+		root = append(root, m.String())
+	}
+	if lookups > MaxLookups {
+		return "", nil, fmt.Errorf("flattened policy needs %d DNS lookups (%d records), more than the limit of %d", lookups, len(parts), MaxLookups)
+	}
+
+	content := strings.Join(root, " ")
+	if size := ResponseSize(domain, content); size > MaxResponseSize {
+		return "", nil, fmt.Errorf("root policy for %s needs a %d-byte response, more than %d", domain, size, MaxResponseSize)
+	}
+	return content, parts, nil
+}
+
+// ResponseSize returns the size of a DNS response carrying a single TXT
+// record with content at name: header, question and one answer whose
+// content is split into 255-byte strings. Other TXT records at the same
+// name add to the real response.
+func ResponseSize(name, content string) int {
+	wireName := len(strings.TrimSuffix(name, ".")) + 2
+	strs := (len(content) + 254) / 255
+	if strs == 0 {
+		strs = 1
+	}
+	// The answer's name is compressed to a 2-byte pointer to the question.
+	return 12 + wireName + 4 + 2 + 10 + strs + len(content)
+}
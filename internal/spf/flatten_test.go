@@ -0,0 +1,171 @@
+package spf
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+type fakeResolver struct {
+	txt, ip, mx map[string][]string
+}
+
+func (f fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return lookupFake(f.txt, name)
+}
+
+func (f fakeResolver) LookupIP(ctx context.Context, name string) ([]string, error) {
+	return lookupFake(f.ip, name)
+}
+
+func (f fakeResolver) LookupMX(ctx context.Context, name string) ([]string, error) {
+	return lookupFake(f.mx, name)
+}
+
+func lookupFake(m map[string][]string, name string) ([]string, error) {
+	v, ok := m[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: NXDOMAIN", name)
+	}
+	return v, nil
+}
+
+func TestFlatten(t *testing.T) {
+	res := fakeResolver{
+		txt: map[string][]string{
+			"_spf.provider.example":  {"v=spf1 ip4:198.51.100.0/24 include:_nets.provider.example -ip4:203.0.113.9 ~all"},
+			"_nets.provider.example": {"v=spf1 ip4:198.51.100.7 ip6:2001:db8::/32 -all"},
+			"_macro.example":         {"v=spf1 exists:%{i}._spf.example -all"},
+		},
+		ip: map[string][]string{
+			"example.com":     {"192.0.2.10", "2001:db8:1::10"},
+			"mx1.example.com": {"192.0.2.20"},
+		},
+		mx: map[string][]string{
+			"example.com": {"mx1.example.com"},
+		},
+	}
+
+	r, err := Parse("v=spf1 a mx/24 include:_spf.provider.example include:_macro.example -ip4:192.0.2.99 -all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := Flatten(context.Background(), r, "example.com", res)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var nets []string
+	for _, p := range f.Networks {
+		nets = append(nets, NetworkTerm(p).String())
+	}
+	want := "ip4:192.0.2.0/24 ip4:198.51.100.0/24 ip6:2001:db8::/32"
+	if got := strings.Join(nets, " "); got != want {
+		t.Errorf("Networks = %q, want %q", got, want)
+	}
+
+	root, parts, err := f.Split("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "v=spf1 include:_spf1.example.com include:_macro.example -ip4:192.0.2.99 -all"; root != want {
+		t.Errorf("root = %q, want %q", root, want)
+	}
+	if len(parts) != 1 || parts[0].Content != "v=spf1 "+strings.Join(nets, " ") {
+		t.Errorf("parts = %+v", parts)
+	}
+}
+
+func TestFlattenKeepsOrder(t *testing.T) {
+	res := fakeResolver{txt: map[string][]string{
+		"_spf.provider.example": {"v=spf1 ip4:198.51.100.0/24 -all"},
+	}}
+	tests := []struct {
+		policy  string
+		want    string
+		wantErr bool
+	}{
+		{
+			policy: "v=spf1 ip4:192.0.2.0/24 -ip4:192.0.2.4 ~all",
+			want:   "v=spf1 include:_spf1.example.com -ip4:192.0.2.4 ~all",
+		},
+		{
+			policy: "v=spf1 ?a:other.example ip4:192.0.2.0/24 ~include:_x.example ip4:203.0.113.1 -all",
+			// ~include may match anything, so 203.0.113.1 cannot move ahead of it.
+			wantErr: true,
+		},
+		{
+			policy: "v=spf1 ip4:192.0.2.0/24 -ip4:203.0.113.9 include:_spf.provider.example ~all",
+			want:   "v=spf1 include:_spf1.example.com -ip4:203.0.113.9 ~all",
+		},
+		{
+			policy:  "v=spf1 ip4:203.0.113.7 -ip4:192.0.2.4 ip4:192.0.2.0/24 ~all",
+			wantErr: true,
+		},
+		{
+			policy: "v=spf1 ip4:192.0.2.0/24 foo=bar include:_spf.provider.example exp=explain.example ~all",
+			want:   "v=spf1 include:_spf1.example.com ~all foo=bar exp=explain.example",
+		},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := Flatten(context.Background(), r, "example.com", res)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Flatten(%q) succeeded, want an error", tt.policy)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Flatten(%q): %v", tt.policy, err)
+			continue
+		}
+		root, _, err := f.Split("example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if root != tt.want {
+			t.Errorf("Flatten(%q) root = %q, want %q", tt.policy, root, tt.want)
+		}
+	}
+}
+
+func TestSplitChainsParts(t *testing.T) {
+	nets := map[netip.Prefix]bool{}
+	for i := 0; i < 100; i++ {
+		nets[netip.MustParsePrefix(fmt.Sprintf("10.%d.%d.0/24", i/10, i))] = true
+	}
+	all, _ := ParseTerm("-all")
+	f := &Flattened{Networks: collapse(nets), All: &all}
+
+	root, parts, err := f.Split("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) < 2 {
+		t.Fatalf("got %d parts, want the ranges split over several", len(parts))
+	}
+	if root != "v=spf1 include:_spf1.example.com -all" {
+		t.Errorf("root = %q", root)
+	}
+
+	seen := 0
+	for i, p := range parts {
+		if size := ResponseSize(p.Name, p.Content); size > MaxResponseSize {
+			t.Errorf("%s needs %d bytes", p.Name, size)
+		}
+		next := i < len(parts)-1
+		if got := strings.HasSuffix(p.Content, " include:"+PartName("example.com", i+2)); got != next {
+			t.Errorf("%s chained = %v, want %v", p.Name, got, next)
+		}
+		seen += strings.Count(p.Content, "ip4:")
+	}
+	if seen != 100 {
+		t.Errorf("parts hold %d ranges, want 100", seen)
+	}
+}
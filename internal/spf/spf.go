@@ -0,0 +1,304 @@
+// Package spf parses and evaluates SPF (RFC 7208) policy records.
+package spf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MaxLookups is the RFC 7208 limit on DNS-querying terms per evaluation.
+const MaxLookups = 10
+
+// Term is a single mechanism ("~all", "include:_spf.google.com") or
+// modifier ("redirect=_spf.example.com").
+type Term struct {
+	// Qualifier is one of '+', '-', '~', '?' for mechanisms, or 0 when
+	// omitted (which means '+').
+	Qualifier byte
+	// Name is the mechanism or modifier name in lower case.
+	Name string
+	// Value is the part after ':' (mechanisms) or '=' (modifiers).
+	Value string
+	// Modifier is true for name=value terms.
+	Modifier bool
+}
+
+func (t Term) String() string {
+	if t.Modifier {
+		return t.Name + "=" + t.Value
+	}
+	s := t.Name
+	if t.Qualifier != 0 {
+		s = string(t.Qualifier) + s
+	}
+	if t.Value != "" {
+		sep := ":"
+		if strings.HasPrefix(t.Value, "/") {
+			sep = ""
+		}
+		s += sep + t.Value
+	}
+	return s
+}
+
+// CausesLookup reports whether evaluating the term requires a DNS query.
+func (t Term) CausesLookup() bool {
+	if t.Modifier {
+		return t.Name == "redirect"
+	}
+	switch t.Name {
+	case "include", "a", "mx", "ptr", "exists":
+		return true
+	}
+	return false
+}
+
+// Record is a parsed SPF policy.
+type Record struct {
+	Terms []Term
+}
+
+// IsSPF reports whether a TXT value is an SPF policy.
+func IsSPF(txt string) bool {
+	txt = strings.ToLower(strings.TrimSpace(txt))
+	return txt == "v=spf1" || strings.HasPrefix(txt, "v=spf1 ")
+}
+
+var mechanisms = map[string]bool{
+	"all": true, "include": true, "a": true, "mx": true,
+	"ptr": true, "ip4": true, "ip6": true, "exists": true,
+}
+
+// Parse parses an SPF policy such as "v=spf1 include:_spf.google.com ~all".
+func Parse(txt string) (*Record, error) {
+	if !IsSPF(txt) {
+		return nil, fmt.Errorf("not an SPF record (must start with v=spf1)")
+	}
+	fields := strings.Fields(txt)[1:]
+
+	r := &Record{}
+	for _, field := range fields {
+		term, err := parseTerm(field)
+		if err != nil {
+			return nil, err
+		}
+		r.Terms = append(r.Terms, term)
+	}
+	return r, r.validate()
+}
+
+// ParseTerm parses a single mechanism or modifier such as "include:x.com",
+// "-all" or "redirect=x.com".
+func ParseTerm(field string) (Term, error) {
+	if field == "" {
+		return Term{}, fmt.Errorf("empty term")
+	}
+	return parseTerm(field)
+}
+
+func parseTerm(field string) (Term, error) {
+	// Unknown modifiers are kept as they are; receivers ignore them
+	// (RFC 7208 section 6).
+	if i := strings.IndexAny(field, "=:/"); i > 0 && field[i] == '=' {
+		return Term{Name: strings.ToLower(field[:i]), Value: field[i+1:], Modifier: true}, nil
+	}
+
+	var t Term
+	switch field[0] {
+	case '+', '-', '~', '?':
+		t.Qualifier = field[0]
+		field = field[1:]
+	}
+
+	name, value := field, ""
+	if i := strings.IndexAny(field, ":/"); i >= 0 {
+		name, value = field[:i], field[i:]
+		value = strings.TrimPrefix(value, ":")
+	}
+	t.Name = strings.ToLower(name)
+	t.Value = value
+
+	if !mechanisms[t.Name] {
+		return Term{}, fmt.Errorf("unknown mechanism %q", field)
+	}
+	switch t.Name {
+	case "include", "exists", "ip4", "ip6":
+		if t.Value == "" {
+			return Term{}, fmt.Errorf("%s requires a value", t.Name)
+		}
+	case "all":
+		if t.Value != "" {
+			return Term{}, fmt.Errorf("all does not take a value")
+		}
+	}
+	return t, nil
+}
+
+func (r *Record) validate() error {
+	seen := map[string]bool{}
+	allSeen := false
+	for _, t := range r.Terms {
+		if t.Modifier {
+			if t.Name != "redirect" && t.Name != "exp" {
+				continue
+			}
+			if seen[t.Name] {
+				return fmt.Errorf("modifier %s appears more than once", t.Name)
+			}
+			seen[t.Name] = true
+			continue
+		}
+		if allSeen {
+			return fmt.Errorf("mechanism %q after \"all\" is never evaluated", t.String())
+		}
+		allSeen = t.Name == "all"
+	}
+	return nil
+}
+
+// Equal reports whether two terms are the same, treating an omitted
+// qualifier as '+'.
+func (t Term) Equal(o Term) bool {
+	q := func(b byte) byte {
+		if b == 0 {
+			return '+'
+		}
+		return b
+	}
+	return t.Name == o.Name && t.Modifier == o.Modifier &&
+		q(t.Qualifier) == q(o.Qualifier) && strings.EqualFold(t.Value, o.Value)
+}
+
+// Add inserts t before the "all" mechanism, or before any modifiers when
+// there is none. Adding "all" replaces the existing one. It returns false
+// when an equal term is already present.
+func (r *Record) Add(t Term) bool {
+	for _, existing := range r.Terms {
+		if existing.Equal(t) {
+			return false
+		}
+	}
+
+	if t.Name == "all" && !t.Modifier {
+		for i, existing := range r.Terms {
+			if existing.Name == "all" && !existing.Modifier {
+				r.Terms[i] = t
+				return true
+			}
+		}
+	}
+
+	at := len(r.Terms)
+	for i, existing := range r.Terms {
+		if existing.Modifier || existing.Name == "all" {
+			at = i
+			break
+		}
+	}
+	r.Terms = append(r.Terms[:at], append([]Term{t}, r.Terms[at:]...)...)
+	return true
+}
+
+// Remove deletes terms matching t. When t has no qualifier, terms with any
+// qualifier match. It returns the number of terms removed.
+func (r *Record) Remove(t Term) int {
+	kept := r.Terms[:0]
+	removed := 0
+	for _, existing := range r.Terms {
+		match := existing.Name == t.Name && existing.Modifier == t.Modifier && strings.EqualFold(existing.Value, t.Value)
+		if match && t.Qualifier != 0 {
+			match = existing.Equal(t)
+		}
+		if match {
+			removed++
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	r.Terms = kept
+	return removed
+}
+
+// Validate checks the record's structure, as Parse does.
+func (r *Record) Validate() error {
+	return r.validate()
+}
+
+func (r *Record) String() string {
+	parts := []string{"v=spf1"}
+	for _, t := range r.Terms {
+		parts = append(parts, t.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// TXTLookup returns the TXT values published at name.
+type TXTLookup func(ctx context.Context, name string) ([]string, error)
+
+// CountLookups returns the number of DNS-querying terms evaluated for r,
+// following include and redirect recursively as a receiver would.
+func CountLookups(ctx context.Context, r *Record, lookup TXTLookup) (int, error) {
+	return countLookups(ctx, r, lookup, map[string]bool{}, 0)
+}
+
+// countLookups walks includes depth first; visited holds the domains on the
+// current include path so that loops are detected.
+func countLookups(ctx context.Context, r *Record, lookup TXTLookup, visited map[string]bool, depth int) (int, error) {
+	if depth > MaxLookups {
+		return 0, fmt.Errorf("include chain is too deep")
+	}
+	count := 0
+	for _, t := range r.Terms {
+		if !t.CausesLookup() {
+			continue
+		}
+		count++
+		if t.Name != "include" && t.Name != "redirect" {
+			continue
+		}
+		domain := strings.ToLower(t.Value)
+		if visited[domain] {
+			return count, fmt.Errorf("include loop at %s", domain)
+		}
+
+		nested, err := Fetch(ctx, domain, lookup)
+		if err != nil {
+			return count, err
+		}
+		visited[domain] = true
+		n, err := countLookups(ctx, nested, lookup, visited, depth+1)
+		delete(visited, domain)
+		count += n
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// Fetch looks up and parses the SPF policy published at domain.
+func Fetch(ctx context.Context, domain string, lookup TXTLookup) (*Record, error) {
+	values, err := lookup(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("lookup SPF for %s: %w", domain, err)
+	}
+	var found []string
+	for _, v := range values {
+		if IsSPF(v) {
+			found = append(found, v)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%s has no SPF record", domain)
+	case 1:
+	default:
+		return nil, fmt.Errorf("%s has %d SPF records", domain, len(found))
+	}
+	r, err := Parse(found[0])
+	if err != nil {
+		return nil, fmt.Errorf("SPF for %s: %w", domain, err)
+	}
+	return r, nil
+}
@@ -0,0 +1,97 @@
+package spf
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "v=spf1 -all", want: "v=spf1 -all"},
+		{in: "v=spf1 ip4:192.0.2.0/24 include:_spf.google.com ~all", want: "v=spf1 ip4:192.0.2.0/24 include:_spf.google.com ~all"},
+		{in: "v=spf1 a/24 mx redirect=_spf.example.com", want: "v=spf1 a/24 mx redirect=_spf.example.com"},
+		{in: "v=spf1 +MX ?all", want: "v=spf1 +mx ?all"},
+		{in: "v=spf1 include:x.com foo=bar -all", want: "v=spf1 include:x.com foo=bar -all"},
+		{in: "v=spf1 -all foo=1 foo=2", want: "v=spf1 -all foo=1 foo=2"},
+		{in: "v=spf1 -all exp=a.example exp=b.example", wantErr: true},
+		{in: "v=spf2 -all", wantErr: true},
+		{in: "v=spf1 foo:bar -all", wantErr: true},
+		{in: "v=spf1 -all mx", wantErr: true},
+		{in: "v=spf1 include: -all", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			r, err := Parse(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && r.String() != tt.want {
+				t.Errorf("String() = %q, want %q", r.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCountLookups(t *testing.T) {
+	zone := map[string][]string{
+		"a.example":    {"v=spf1 include:b.example mx -all"},
+		"b.example":    {"v=spf1 a ip4:192.0.2.1 include:c.example -all"},
+		"c.example":    {"v=spf1 exists:%{i}.x.example -all"},
+		"loop.example": {"v=spf1 include:loop.example -all"},
+	}
+	lookup := func(ctx context.Context, name string) ([]string, error) {
+		if v, ok := zone[name]; ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("NXDOMAIN")
+	}
+
+	r, _ := Parse("v=spf1 include:a.example ~all")
+	n, err := CountLookups(context.Background(), r, lookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// include:a, include:b, mx, a, include:c, exists
+	if n != 6 {
+		t.Errorf("CountLookups() = %d, want 6", n)
+	}
+
+	r, _ = Parse("v=spf1 include:loop.example -all")
+	if _, err := CountLookups(context.Background(), r, lookup); err == nil {
+		t.Error("expected error for include loop")
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	r, _ := Parse("v=spf1 mx ~all")
+
+	include, _ := ParseTerm("include:_spf.google.com")
+	if !r.Add(include) {
+		t.Fatal("Add() = false for new term")
+	}
+	if r.Add(include) {
+		t.Error("Add() = true for duplicate term")
+	}
+	if got := r.String(); got != "v=spf1 mx include:_spf.google.com ~all" {
+		t.Errorf("after Add: %q", got)
+	}
+
+	all, _ := ParseTerm("-all")
+	r.Add(all)
+	if got := r.String(); got != "v=spf1 mx include:_spf.google.com -all" {
+		t.Errorf("after Add(-all): %q", got)
+	}
+
+	mx, _ := ParseTerm("mx")
+	if n := r.Remove(mx); n != 1 {
+		t.Errorf("Remove() = %d, want 1", n)
+	}
+	if got := r.String(); got != "v=spf1 include:_spf.google.com -all" {
+		t.Errorf("after Remove: %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+)
+
+// Column identifies a sortable table column.
+type Column int
+
+const (
+	ColType Column = iota
+	ColName
+	ColContent
+	ColTTL
+	ColProxied
+	numColumns
+)
+
+var columnTitles = [numColumns]string{"Type", "Name", "Content", "TTL", "Proxied"}
+
+// filterRecords returns the records matching every whitespace-separated
+// term of query. A term matches when it is a case-insensitive substring of
+// the record's type, name or content; "type:mx", "name:www" and
+// "content:1.2" restrict a term to one field.
+func filterRecords(records []cloudflare.DNSRecord, query string) []cloudflare.DNSRecord {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return records
+	}
+	var out []cloudflare.DNSRecord
+	for _, r := range records {
+		if matchesAll(r, terms) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func matchesAll(r cloudflare.DNSRecord, terms []string) bool {
+	fields := map[string]string{
+		"type":    strings.ToLower(r.Type),
+		"name":    strings.ToLower(r.Name),
+		"content": strings.ToLower(r.Content),
+	}
+	for _, term := range terms {
+		if field, value, ok := strings.Cut(term, ":"); ok {
+			if f, known := fields[field]; known {
+				if field == "type" && f != value || !strings.Contains(f, value) {
+					return false
+				}
+				continue
+			}
+		}
+		if !strings.Contains(fields["type"], term) && !strings.Contains(fields["name"], term) && !strings.Contains(fields["content"], term) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortRecords sorts records in place by col, breaking ties by name, type
+// and content so the order is stable across refreshes.
+func sortRecords(records []cloudflare.DNSRecord, col Column, desc bool) {
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		c := compare(cell(a, col), cell(b, col), col == ColTTL)
+		if c == 0 {
+			for _, tie := range []Column{ColName, ColType, ColContent} {
+				if c = compare(cell(a, tie), cell(b, tie), false); c != 0 {
+					break
+				}
+			}
+			return c < 0
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+func compare(a, b string, numeric bool) int {
+	if numeric {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// cell returns the text shown for r in col.
+func cell(r cloudflare.DNSRecord, col Column) string {
+	switch col {
+	case ColType:
+		return r.Type
+	case ColName:
+		return r.Name
+	case ColContent:
+		if r.Priority != nil && (r.Type == "MX" || r.Type == "SRV") {
+			return strconv.Itoa(int(*r.Priority)) + " " + r.Content
+		}
+		return r.Content
+	case ColTTL:
+		if r.TTL == 1 {
+			return "auto"
+		}
+		return strconv.Itoa(r.TTL)
+	case ColProxied:
+		if r.Proxied != nil && *r.Proxied {
+			return "yes"
+		}
+		return "no"
+	}
+	return ""
+}
+
+// proxiable reports whether Cloudflare can proxy records of type t.
+func proxiable(t string) bool {
+	switch strings.ToUpper(t) {
+	case "A", "AAAA", "CNAME":
+		return true
+	}
+	return false
+}
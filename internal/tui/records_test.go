@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+)
+
+var testRecords = []cloudflare.DNSRecord{
+	{Type: "A", Name: "www.example.com", Content: "192.0.2.1", TTL: 300},
+	{Type: "MX", Name: "example.com", Content: "mx.example.net", TTL: 3600},
+	{Type: "TXT", Name: "example.com", Content: "v=spf1 mx -all", TTL: 1},
+	{Type: "AAAA", Name: "www.example.com", Content: "2001:db8::1", TTL: 300},
+}
+
+func TestFilterRecords(t *testing.T) {
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", 4},
+		{"www", 2},
+		{"type:a", 1},
+		{"type:aaaa", 1},
+		{"mx", 2},
+		{"example.com spf", 1},
+		{"name:www content:192", 1},
+		{"nomatch", 0},
+	}
+	for _, tt := range tests {
+		if got := filterRecords(testRecords, tt.query); len(got) != tt.want {
+			t.Errorf("filterRecords(%q) returned %d records, want %d", tt.query, len(got), tt.want)
+		}
+	}
+}
+
+func TestSortRecords(t *testing.T) {
+	records := append([]cloudflare.DNSRecord(nil), testRecords...)
+
+	sortRecords(records, ColTTL, false)
+	if records[0].Type != "TXT" || records[3].Type != "MX" {
+		t.Errorf("by TTL: got %s first and %s last", records[0].Type, records[3].Type)
+	}
+
+	sortRecords(records, ColName, true)
+	if records[0].Name != "www.example.com" || records[0].Type != "A" {
+		t.Errorf("by name desc: got %s %s first", records[0].Type, records[0].Name)
+	}
+}
@@ -0,0 +1,630 @@
+// Package tui implements an interactive terminal browser for a zone's DNS
+// records.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/rjshrjndrn/cloudflare-cli/internal/cloudflare"
+)
+
+// Options configures the TUI.
+type Options struct {
+	Client *cloudflare.Client
+	// Zone is the zone to open. When empty the zone picker is shown first.
+	Zone string
+	// Account is the name of the current account, if any.
+	Account string
+	// Accounts lists the configured account names. Account switching is
+	// offered when Connect is set and there is more than one.
+	Accounts []string
+	// Connect returns a client for the named account and the account's
+	// default domain.
+	Connect func(account string) (*cloudflare.Client, string, error)
+}
+
+// Run starts the TUI and blocks until the user quits.
+func Run(opts Options) error {
+	_, err := tea.NewProgram(newModel(opts), tea.WithAltScreen()).Run()
+	return err
+}
+
+type mode int
+
+const (
+	modeTable mode = iota
+	modeFilter
+	modeEdit
+	modeConfirmDelete
+	modePickZone
+	modePickAccount
+)
+
+const (
+	fieldName = iota
+	fieldContent
+	fieldTTL
+	fieldPriority
+	numFields
+)
+
+var fieldLabels = [numFields]string{"Name", "Content", "TTL", "Priority"}
+
+var (
+	titleStyle  = lipgloss.NewStyle().Bold(true)
+	errorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	helpStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	pickedStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+type model struct {
+	opts    Options
+	client  *cloudflare.Client
+	zone    string
+	account string
+	// zoneClient is the client for the zone whose records are shown; each
+	// load gets its own so that a zone switch in progress cannot redirect
+	// edits.
+	zoneClient *cloudflare.Client
+
+	records []cloudflare.DNSRecord // the whole zone
+	view    []cloudflare.DNSRecord // filtered and sorted, as shown
+	table   table.Model
+
+	mode     mode
+	filter   textinput.Model
+	sortCol  Column
+	sortDesc bool
+
+	editing cloudflare.DNSRecord
+	fields  [numFields]textinput.Model
+	focus   int
+
+	choices []string
+	choice  int
+
+	loading bool
+	status  string
+	err     error
+	width   int
+	height  int
+}
+
+type (
+	recordsMsg struct {
+		client  *cloudflare.Client
+		records []cloudflare.DNSRecord
+	}
+	zonesMsg     []string
+	updatedMsg   cloudflare.DNSRecord
+	deletedMsg   cloudflare.DNSRecord
+	errMsg       struct{ err error }
+	connectedMsg struct {
+		client  *cloudflare.Client
+		account string
+		domain  string
+	}
+)
+
+func newModel(opts Options) model {
+	filter := textinput.New()
+	filter.Prompt = "/"
+	filter.Placeholder = "filter (type:mx name:www ...)"
+
+	var fields [numFields]textinput.Model
+	for i := range fields {
+		fields[i] = textinput.New()
+		fields[i].Prompt = fmt.Sprintf("%-9s", fieldLabels[i]+":")
+	}
+
+	t := table.New(table.WithFocused(true))
+	return model{
+		opts:    opts,
+		client:  opts.Client,
+		zone:    opts.Zone,
+		account: opts.Account,
+		table:   t,
+		filter:  filter,
+		fields:  fields,
+		sortCol: ColName,
+		loading: true,
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	if m.zone == "" {
+		return m.loadZones()
+	}
+	return m.loadRecords(m.zone)
+}
+
+func (m model) loadRecords(zone string) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx := context.Background()
+		zc, err := client.ForZone(ctx, zone)
+		if err != nil {
+			return errMsg{err}
+		}
+		records, err := zc.ListDNSRecords(ctx)
+		if err != nil {
+			return errMsg{err}
+		}
+		return recordsMsg{zc, records}
+	}
+}
+
+func (m model) loadZones() tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		zones, err := client.ListZones(context.Background())
+		if err != nil {
+			return errMsg{err}
+		}
+		names := make([]string, len(zones))
+		for i, z := range zones {
+			names[i] = z.Name
+		}
+		return zonesMsg(names)
+	}
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.layout()
+		return m, nil
+
+	case recordsMsg:
+		m.loading = false
+		m.zoneClient = msg.client
+		m.zone = msg.client.ZoneName()
+		m.records = msg.records
+		m.status = fmt.Sprintf("%d records", len(msg.records))
+		m.err = nil
+		m.mode = modeTable
+		m.refresh()
+		return m, nil
+
+	case zonesMsg:
+		m.loading = false
+		if len(msg) == 0 {
+			m.err = fmt.Errorf("no zones are visible to this token")
+			return m, nil
+		}
+		m.choices, m.choice, m.mode = msg, 0, modePickZone
+		for i, name := range msg {
+			if strings.EqualFold(name, m.zone) {
+				m.choice = i
+			}
+		}
+		return m, nil
+
+	case updatedMsg:
+		m.loading = false
+		for i := range m.records {
+			if m.records[i].ID == msg.ID {
+				m.records[i] = cloudflare.DNSRecord(msg)
+			}
+		}
+		m.status = fmt.Sprintf("✓ Updated %s %s", msg.Type, msg.Name)
+		m.err = nil
+		m.refresh()
+		return m, nil
+
+	case deletedMsg:
+		m.loading = false
+		kept := m.records[:0]
+		for _, r := range m.records {
+			if r.ID != msg.ID {
+				kept = append(kept, r)
+			}
+		}
+		m.records = kept
+		m.status = fmt.Sprintf("✓ Deleted %s %s", msg.Type, msg.Name)
+		m.err = nil
+		m.refresh()
+		return m, nil
+
+	case connectedMsg:
+		m.client, m.account, m.zone = msg.client, msg.account, msg.domain
+		m.zoneClient = nil
+		m.records, m.view = nil, nil
+		m.refresh()
+		m.loading = true
+		if m.zone == "" {
+			return m, m.loadZones()
+		}
+		return m, m.loadRecords(m.zone)
+
+	case errMsg:
+		m.loading = false
+		m.err = msg.err
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case modeFilter:
+			return m.updateFilter(msg)
+		case modeEdit:
+			return m.updateEdit(msg)
+		case modeConfirmDelete:
+			return m.updateConfirmDelete(msg)
+		case modePickZone, modePickAccount:
+			return m.updatePicker(msg)
+		}
+		return m.updateTable(msg)
+	}
+	return m, nil
+}
+
+func (m model) updateTable(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.loading {
+		// Keys that start a request wait until the current one is done.
+		switch msg.String() {
+		case "r", "z", "a", "enter", "e", "p", "d", "delete":
+			m.status = "Busy, try again in a moment"
+			return m, nil
+		}
+	}
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "/":
+		m.mode = modeFilter
+		return m, m.filter.Focus()
+	case "s":
+		m.sortCol = (m.sortCol + 1) % numColumns
+		m.refresh()
+		return m, nil
+	case "S":
+		m.sortDesc = !m.sortDesc
+		m.refresh()
+		return m, nil
+	case "r":
+		m.loading = true
+		return m, m.loadRecords(m.zone)
+	case "z":
+		m.loading = true
+		return m, m.loadZones()
+	case "a":
+		if m.opts.Connect == nil || len(m.opts.Accounts) < 2 {
+			m.err = fmt.Errorf("no other accounts are configured")
+			return m, nil
+		}
+		m.choices, m.choice, m.mode = m.opts.Accounts, 0, modePickAccount
+		for i, name := range m.choices {
+			if name == m.account {
+				m.choice = i
+			}
+		}
+		return m, nil
+	}
+
+	record, ok := m.selected()
+	if !ok {
+		var cmd tea.Cmd
+		m.table, cmd = m.table.Update(msg)
+		return m, cmd
+	}
+	switch msg.String() {
+	case "enter", "e":
+		cmd := m.startEdit(record)
+		return m, cmd
+	case "p":
+		if !proxiable(record.Type) {
+			m.err = fmt.Errorf("%s records cannot be proxied", record.Type)
+			return m, nil
+		}
+		proxied := record.Proxied == nil || !*record.Proxied
+		m.loading = true
+		return m, m.update(record, record.Name, record.Content, record.TTL, record.Priority, proxied)
+	case "d", "delete":
+		m.mode = modeConfirmDelete
+		m.editing = record
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+func (m model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.filter.SetValue("")
+		fallthrough
+	case "enter":
+		m.filter.Blur()
+		m.mode = modeTable
+		m.refresh()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.refresh()
+	return m, cmd
+}
+
+func (m *model) startEdit(r cloudflare.DNSRecord) tea.Cmd {
+	m.mode = modeEdit
+	m.editing = r
+	m.focus = fieldContent
+	m.fields[fieldName].SetValue(r.Name)
+	m.fields[fieldContent].SetValue(r.Content)
+	m.fields[fieldTTL].SetValue(cell(r, ColTTL))
+	m.fields[fieldPriority].SetValue("")
+	if r.Priority != nil {
+		m.fields[fieldPriority].SetValue(strconv.Itoa(int(*r.Priority)))
+	}
+	for i := range m.fields {
+		m.fields[i].Blur()
+	}
+	return m.fields[m.focus].Focus()
+}
+
+func (m model) editableFields() int {
+	if m.editing.Type == "MX" || m.editing.Type == "SRV" {
+		return numFields
+	}
+	return fieldPriority
+}
+
+func (m model) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = modeTable
+		return m, nil
+	case "tab", "down", "shift+tab", "up":
+		m.fields[m.focus].Blur()
+		n := m.editableFields()
+		if s := msg.String(); s == "shift+tab" || s == "up" {
+			m.focus = (m.focus + n - 1) % n
+		} else {
+			m.focus = (m.focus + 1) % n
+		}
+		return m, m.fields[m.focus].Focus()
+	case "enter":
+		name := strings.TrimSpace(m.fields[fieldName].Value())
+		content := strings.TrimSpace(m.fields[fieldContent].Value())
+		if name == "" || content == "" {
+			m.err = fmt.Errorf("name and content are required")
+			return m, nil
+		}
+		ttl := 1
+		if v := strings.TrimSpace(m.fields[fieldTTL].Value()); v != "" && v != "auto" {
+			n, err := strconv.Atoi(v)
+			if err != nil || (n != 1 && (n < 60 || n > 86400)) {
+				m.err = fmt.Errorf("TTL must be auto, 1 or 60-86400")
+				return m, nil
+			}
+			ttl = n
+		}
+		priority := m.editing.Priority
+		if m.editableFields() == numFields {
+			n, err := strconv.ParseUint(strings.TrimSpace(m.fields[fieldPriority].Value()), 10, 16)
+			if err != nil {
+				m.err = fmt.Errorf("priority must be a number from 0 to 65535")
+				return m, nil
+			}
+			p := uint16(n)
+			priority = &p
+		}
+		proxied := m.editing.Proxied != nil && *m.editing.Proxied
+		m.mode = modeTable
+		m.loading = true
+		return m, m.update(m.editing, name, content, ttl, priority, proxied)
+	}
+	var cmd tea.Cmd
+	m.fields[m.focus], cmd = m.fields[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m model) updateConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.mode = modeTable
+	if msg.String() != "y" && msg.String() != "Y" {
+		m.status = "Delete cancelled"
+		return m, nil
+	}
+	record, client := m.editing, m.zoneClient
+	m.loading = true
+	return m, func() tea.Msg {
+		if err := client.DeleteDNSRecord(context.Background(), record.ID); err != nil {
+			return errMsg{err}
+		}
+		return deletedMsg(record)
+	}
+}
+
+func (m model) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.choice > 0 {
+			m.choice--
+		}
+	case "down", "j":
+		if m.choice < len(m.choices)-1 {
+			m.choice++
+		}
+	case "esc", "q":
+		if m.zone == "" {
+			return m, tea.Quit
+		}
+		m.mode = modeTable
+	case "enter":
+		picked := m.choices[m.choice]
+		m.loading = true
+		if m.mode == modePickZone {
+			m.mode = modeTable
+			return m, m.loadRecords(picked)
+		}
+		m.mode = modeTable
+		connect := m.opts.Connect
+		return m, func() tea.Msg {
+			client, domain, err := connect(picked)
+			if err != nil {
+				return errMsg{err}
+			}
+			return connectedMsg{client, picked, domain}
+		}
+	}
+	return m, nil
+}
+
+func (m model) update(r cloudflare.DNSRecord, name, content string, ttl int, priority *uint16, proxied bool) tea.Cmd {
+	client := m.zoneClient
+	return func() tea.Msg {
+		updated, err := client.UpdateDNSRecord(context.Background(), r.ID, r.Type, name, content, ttl, priority, proxied)
+		if err != nil {
+			return errMsg{err}
+		}
+		return updatedMsg(*updated)
+	}
+}
+
+func (m model) selected() (cloudflare.DNSRecord, bool) {
+	i := m.table.Cursor()
+	if i < 0 || i >= len(m.view) {
+		return cloudflare.DNSRecord{}, false
+	}
+	return m.view[i], true
+}
+
+// refresh rebuilds the visible rows from the records, filter and sort
+// order, keeping the cursor on the same record where possible.
+func (m *model) refresh() {
+	current, hadCurrent := m.selected()
+
+	m.view = filterRecords(append([]cloudflare.DNSRecord(nil), m.records...), m.filter.Value())
+	sortRecords(m.view, m.sortCol, m.sortDesc)
+
+	rows := make([]table.Row, len(m.view))
+	cursor := 0
+	for i, r := range m.view {
+		row := make(table.Row, numColumns)
+		for c := Column(0); c < numColumns; c++ {
+			row[c] = cell(r, c)
+		}
+		rows[i] = row
+		if hadCurrent && r.ID == current.ID {
+			cursor = i
+		}
+	}
+	m.layout()
+	m.table.SetRows(rows)
+	m.table.SetCursor(cursor)
+}
+
+// layout sizes the table columns to the terminal.
+func (m *model) layout() {
+	width := m.width
+	if width == 0 {
+		width = 100
+	}
+	fixed := [numColumns]int{ColType: 6, ColTTL: 6, ColProxied: 7}
+	flexible := width - fixed[ColType] - fixed[ColTTL] - fixed[ColProxied] - 2*int(numColumns)
+	fixed[ColName] = max(flexible*2/5, 10)
+	fixed[ColContent] = max(flexible-fixed[ColName], 10)
+
+	cols := make([]table.Column, numColumns)
+	for c := Column(0); c < numColumns; c++ {
+		title := columnTitles[c]
+		if c == m.sortCol {
+			if m.sortDesc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		cols[c] = table.Column{Title: title, Width: fixed[c]}
+	}
+	m.table.SetColumns(cols)
+	if m.height > 0 {
+		m.table.SetHeight(max(m.height-6, 3))
+	}
+}
+
+func (m model) View() string {
+	var b strings.Builder
+
+	title := "cfcli"
+	if m.account != "" {
+		title += " · " + m.account
+	}
+	if m.zone != "" {
+		title += " · " + m.zone
+	}
+	b.WriteString(titleStyle.Render(title))
+	if m.loading {
+		b.WriteString(helpStyle.Render("  loading…"))
+	}
+	b.WriteString("\n")
+
+	switch m.mode {
+	case modePickZone, modePickAccount:
+		what := "zone"
+		if m.mode == modePickAccount {
+			what = "account"
+		}
+		b.WriteString("Select " + what + ":\n")
+		for i, c := range m.choices {
+			line := "  " + c
+			if i == m.choice {
+				line = pickedStyle.Render("> " + c)
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString(m.footer("↑/↓ move · enter select · esc cancel"))
+		return b.String()
+
+	case modeEdit:
+		fmt.Fprintf(&b, "Edit %s record\n\n", m.editing.Type)
+		for i := 0; i < m.editableFields(); i++ {
+			b.WriteString(m.fields[i].View() + "\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(m.footer("tab next field · enter save · esc cancel"))
+		return b.String()
+	}
+
+	if m.mode == modeFilter || m.filter.Value() != "" {
+		b.WriteString(m.filter.View() + "\n")
+	}
+	b.WriteString(m.table.View() + "\n")
+
+	if m.mode == modeConfirmDelete {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Delete %s %s → %s? (y/N)", m.editing.Type, m.editing.Name, m.editing.Content)) + "\n")
+		return b.String()
+	}
+	help := "enter edit · p proxy · d delete · / filter · s sort · S reverse · r refresh · z zone"
+	if m.opts.Connect != nil && len(m.opts.Accounts) > 1 {
+		help += " · a account"
+	}
+	b.WriteString(m.footer(help + " · q quit"))
+	return b.String()
+}
+
+func (m model) footer(help string) string {
+	var b strings.Builder
+	switch {
+	case m.err != nil:
+		b.WriteString(errorStyle.Render("✗ "+m.err.Error()) + "\n")
+	case m.status != "":
+		b.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+	b.WriteString(helpStyle.Render(help))
+	return b.String()
+}
@@ -0,0 +1,400 @@
+// Package zonesettings describes the zone settings cfcli can change, and
+// parses, validates and compares their values.
+package zonesettings
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Kind is the type of a setting's value.
+type Kind int
+
+const (
+	// OnOff settings take "on" or "off".
+	OnOff Kind = iota
+	// Enum settings take one of a fixed set of strings.
+	Enum
+	// Int settings take a number within a range.
+	Int
+	// HSTS is the security_header setting, an object of HSTS fields.
+	HSTS
+)
+
+// Setting describes a zone setting.
+type Setting struct {
+	ID          string
+	Kind        Kind
+	Values      []string // allowed values for Enum
+	Min, Max    int      // allowed range for Int
+	Description string
+}
+
+var settings = []Setting{
+	{ID: "0rtt", Kind: OnOff, Description: "TLS 1.3 0-RTT session resumption"},
+	{ID: "always_online", Kind: OnOff, Description: "Serve cached pages when the origin is down"},
+	{ID: "always_use_https", Kind: OnOff, Description: "Redirect all HTTP requests to HTTPS"},
+	{ID: "automatic_https_rewrites", Kind: OnOff, Description: "Rewrite HTTP links to HTTPS in HTML"},
+	{ID: "brotli", Kind: OnOff, Description: "Brotli compression"},
+	{ID: "browser_cache_ttl", Kind: Int, Min: 0, Max: 31536000, Description: "Browser cache TTL in seconds (0 respects origin headers)"},
+	{ID: "browser_check", Kind: OnOff, Description: "Browser integrity check"},
+	{ID: "cache_level", Kind: Enum, Values: []string{"basic", "simplified", "aggressive"}, Description: "How query strings affect caching"},
+	{ID: "challenge_ttl", Kind: Int, Min: 300, Max: 31536000, Description: "Seconds a passed challenge stays valid"},
+	{ID: "development_mode", Kind: OnOff, Description: "Bypass the cache for three hours"},
+	{ID: "early_hints", Kind: OnOff, Description: "Send 103 Early Hints"},
+	{ID: "email_obfuscation", Kind: OnOff, Description: "Hide email addresses from bots"},
+	{ID: "hotlink_protection", Kind: OnOff, Description: "Block image hotlinking"},
+	{ID: "http3", Kind: OnOff, Description: "HTTP/3 (QUIC)"},
+	{ID: "ip_geolocation", Kind: OnOff, Description: "Add the CF-IPCountry header"},
+	{ID: "ipv6", Kind: OnOff, Description: "IPv6 compatibility"},
+	{ID: "max_upload", Kind: Int, Min: 100, Max: 500, Description: "Maximum upload size in MB"},
+	{ID: "min_tls_version", Kind: Enum, Values: []string{"1.0", "1.1", "1.2", "1.3"}, Description: "Minimum TLS version"},
+	{ID: "opportunistic_encryption", Kind: OnOff, Description: "Advertise HTTPS to HTTP/2 clients"},
+	{ID: "opportunistic_onion", Kind: OnOff, Description: "Onion routing for Tor clients"},
+	{ID: "pseudo_ipv4", Kind: Enum, Values: []string{"off", "add_header", "overwrite_header"}, Description: "Pseudo IPv4 header for IPv6 clients"},
+	{ID: "rocket_loader", Kind: OnOff, Description: "Defer loading of JavaScript"},
+	{ID: "security_header", Kind: HSTS, Description: "HTTP Strict Transport Security (HSTS)"},
+	{ID: "security_level", Kind: Enum, Values: []string{"off", "essentially_off", "low", "medium", "high", "under_attack"}, Description: "Challenge threshold for visitors"},
+	{ID: "ssl", Kind: Enum, Values: []string{"off", "flexible", "full", "strict"}, Description: "SSL/TLS encryption mode"},
+	{ID: "tls_1_3", Kind: Enum, Values: []string{"on", "off", "zrt"}, Description: "TLS 1.3 (zrt also enables 0-RTT)"},
+	{ID: "websockets", Kind: OnOff, Description: "WebSocket connections"},
+}
+
+// aliases maps friendlier names to setting IDs.
+var aliases = map[string]string{
+	"hsts":     "security_header",
+	"ssl_mode": "ssl",
+	"tls1.3":   "tls_1_3",
+	"tls_1.3":  "tls_1_3",
+	"min_tls":  "min_tls_version",
+	"http/3":   "http3",
+	"zero_rtt": "0rtt",
+}
+
+// hstsFields are the fields of security_header's strict_transport_security
+// object, in display order.
+var hstsFields = []string{"enabled", "max_age", "include_subdomains", "preload", "nosniff"}
+
+// All returns the known settings, sorted by ID.
+func All() []Setting {
+	return settings
+}
+
+// Lookup returns the setting with the given ID or alias. Dashes are taken
+// as underscores, so "always-use-https" works too.
+func Lookup(name string) (Setting, bool) {
+	id := CanonicalID(name)
+	for _, s := range settings {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// CanonicalID returns the setting ID for name, resolving aliases.
+func CanonicalID(name string) string {
+	id := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+	if a, ok := aliases[id]; ok {
+		return a
+	}
+	return id
+}
+
+// Names returns the IDs of the known settings.
+func Names() []string {
+	names := make([]string, len(settings))
+	for i, s := range settings {
+		names[i] = s.ID
+	}
+	return names
+}
+
+// Parse converts input to the value sent to the API for the setting. HSTS
+// takes comma-separated field=value pairs, e.g. "enabled=true,max_age=31536000";
+// fields not given keep their value from current, the setting's present
+// value.
+func (s Setting) Parse(input string, current any) (any, error) {
+	input = strings.TrimSpace(input)
+	switch s.Kind {
+	case OnOff:
+		on, err := parseBool(input)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.ID, err)
+		}
+		if on {
+			return "on", nil
+		}
+		return "off", nil
+
+	case Enum:
+		v := strings.ToLower(input)
+		for _, allowed := range s.Values {
+			if v == allowed {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("%s: invalid value %q (use %s)", s.ID, input, strings.Join(s.Values, ", "))
+
+	case Int:
+		n, err := strconv.Atoi(input)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid number %q", s.ID, input)
+		}
+		if n < s.Min || n > s.Max {
+			return nil, fmt.Errorf("%s: %d is out of range (%d-%d)", s.ID, n, s.Min, s.Max)
+		}
+		return n, nil
+
+	case HSTS:
+		return s.parseHSTS(input, current)
+	}
+	return nil, fmt.Errorf("%s: unsupported setting", s.ID)
+}
+
+func (s Setting) parseHSTS(input string, current any) (any, error) {
+	fields := hstsValues(current)
+	if fields == nil {
+		fields = map[string]any{"enabled": false, "max_age": 0, "include_subdomains": false, "preload": false, "nosniff": false}
+	}
+
+	// "on" and "off" toggle HSTS and keep the other fields.
+	if on, err := parseBool(input); err == nil && !strings.Contains(input, "=") {
+		fields["enabled"] = on
+		return hstsValue(fields), nil
+	}
+
+	for _, pair := range strings.Split(input, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		key = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), "-", "_")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s: expected field=value, got %q", s.ID, pair)
+		}
+		switch key {
+		case "max_age":
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%s: invalid max_age %q", s.ID, value)
+			}
+			fields[key] = n
+		case "enabled", "include_subdomains", "preload", "nosniff":
+			b, err := parseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", s.ID, key, err)
+			}
+			fields[key] = b
+		default:
+			return nil, fmt.Errorf("%s: unknown field %q (use %s)", s.ID, key, strings.Join(hstsFields, ", "))
+		}
+	}
+	if max, _ := toInt(fields["max_age"]); fields["preload"] == true && max < 31536000 {
+		return nil, fmt.Errorf("%s: preload requires max_age of at least 31536000 (one year)", s.ID)
+	}
+	return hstsValue(fields), nil
+}
+
+// hstsValues returns the strict_transport_security fields of a
+// security_header value as returned by the API, or nil.
+func hstsValues(v any) map[string]any {
+	outer, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	inner, ok := outer["strict_transport_security"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	fields := make(map[string]any, len(inner))
+	for k, v := range inner {
+		fields[k] = v
+	}
+	return fields
+}
+
+func hstsValue(fields map[string]any) map[string]any {
+	return map[string]any{"strict_transport_security": fields}
+}
+
+// Format renders a setting value for display and comparison. Equal values
+// format the same whatever their origin, so values decoded from the API can
+// be compared to parsed ones.
+func Format(id string, v any) string {
+	if id == "security_header" {
+		if fields := hstsValues(v); fields != nil {
+			parts := make([]string, 0, len(hstsFields))
+			for _, k := range hstsFields {
+				parts = append(parts, fmt.Sprintf("%s=%s", k, formatScalar(fields[k])))
+			}
+			return strings.Join(parts, ",")
+		}
+	}
+	switch v.(type) {
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+	return formatScalar(v)
+}
+
+func formatScalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func toInt(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "on", "true", "yes", "enabled", "1":
+		return true, nil
+	case "off", "false", "no", "disabled", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid value %q (use on or off)", s)
+}
+
+// Difference is a setting whose value differs between two zones.
+type Difference struct {
+	ID         string `json:"id"`
+	Value      string `json:"value"`
+	OtherValue string `json:"other_value"`
+}
+
+// Diff compares two zones' settings, keyed by ID, and returns the settings
+// that differ, sorted by ID. A setting missing from one side formats as "-".
+func Diff(a, b map[string]any) []Difference {
+	ids := map[string]bool{}
+	for id := range a {
+		ids[id] = true
+	}
+	for id := range b {
+		ids[id] = true
+	}
+
+	var diffs []Difference
+	for id := range ids {
+		x, y := Format(id, a[id]), Format(id, b[id])
+		if x != y {
+			diffs = append(diffs, Difference{ID: id, Value: x, OtherValue: y})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].ID < diffs[j].ID })
+	return diffs
+}
+
+// Change is a setting update computed by Plan.
+type Change struct {
+	ID    string
+	Old   string
+	New   string
+	Value any
+}
+
+// ParseBaseline reads a baseline file: a YAML map from setting names to
+// values, with HSTS given as a map of its fields. Scalars are kept as
+// written, so "min_tls_version: 1.0" means "1.0" rather than the number 1.
+func ParseBaseline(data []byte) (map[string]any, error) {
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	baseline := make(map[string]any, len(doc))
+	for name, node := range doc {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			baseline[name] = node.Value
+		case yaml.MappingNode:
+			fields := map[string]any{}
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				fields[node.Content[i].Value] = node.Content[i+1].Value
+			}
+			baseline[name] = fields
+		default:
+			return nil, fmt.Errorf("line %d: %s must be a value or a map of fields", node.Line, name)
+		}
+	}
+	return baseline, nil
+}
+
+// Plan works out the updates needed to bring current, the zone's settings
+// keyed by ID, to the values in baseline, keyed by setting name or alias.
+// Baseline values are strings, as typed on the command line, or for HSTS
+// a map of fields. All problems are returned together.
+func Plan(current map[string]any, baseline map[string]any) ([]Change, error) {
+	names := make([]string, 0, len(baseline))
+	for name := range baseline {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var changes []Change
+	var problems []string
+	seen := map[string]string{}
+	for _, name := range names {
+		s, ok := Lookup(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown setting %q", name))
+			continue
+		}
+		if prev, dup := seen[s.ID]; dup {
+			problems = append(problems, fmt.Sprintf("%q and %q both set %s", prev, name, s.ID))
+			continue
+		}
+		seen[s.ID] = name
+
+		value, err := s.Parse(baselineInput(baseline[name]), current[s.ID])
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		old, new := Format(s.ID, current[s.ID]), Format(s.ID, value)
+		if old != new {
+			changes = append(changes, Change{ID: s.ID, Old: old, New: new, Value: value})
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid baseline:\n  %s", strings.Join(problems, "\n  "))
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes, nil
+}
+
+// baselineInput turns a value decoded from a baseline file into the
+// command-line form Parse takes.
+func baselineInput(v any) string {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, m[k])
+	}
+	return strings.Join(pairs, ",")
+}
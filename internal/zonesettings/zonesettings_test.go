@@ -0,0 +1,141 @@
+package zonesettings
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// apiValue round-trips v through JSON, as values returned by the API are.
+func apiValue(t *testing.T, v any) any {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name, input string
+		want        string
+		wantErr     bool
+	}{
+		{"always-use-https", "true", "on", false},
+		{"brotli", "OFF", "off", false},
+		{"ipv6", "maybe", "", true},
+		{"ssl", "Strict", "strict", false},
+		{"ssl_mode", "medium", "", true},
+		{"min_tls_version", "1.2", "1.2", false},
+		{"min_tls_version", "1.4", "", true},
+		{"browser_cache_ttl", "14400", "14400", false},
+		{"max_upload", "1000", "", true},
+		{"challenge_ttl", "abc", "", true},
+	}
+	for _, tt := range tests {
+		s, ok := Lookup(tt.name)
+		if !ok {
+			t.Fatalf("Lookup(%q) failed", tt.name)
+		}
+		v, err := s.Parse(tt.input, nil)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Parse(%q) error = %v, wantErr %v", tt.name, tt.input, err, tt.wantErr)
+			continue
+		}
+		if err == nil && Format(s.ID, v) != tt.want {
+			t.Errorf("%s: Parse(%q) = %s, want %s", tt.name, tt.input, Format(s.ID, v), tt.want)
+		}
+	}
+}
+
+func TestParseHSTS(t *testing.T) {
+	s, _ := Lookup("hsts")
+	current := apiValue(t, hstsValue(map[string]any{
+		"enabled": true, "max_age": 15552000, "include_subdomains": false, "preload": false, "nosniff": true,
+	}))
+
+	v, err := s.Parse("max-age=31536000,include_subdomains=on", current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "enabled=true,max_age=31536000,include_subdomains=true,preload=false,nosniff=true"
+	if got := Format(s.ID, v); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	v, err = s.Parse("off", current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Format(s.ID, v); !strings.HasPrefix(got, "enabled=false,max_age=15552000") {
+		t.Errorf("off: got %s", got)
+	}
+
+	for _, input := range []string{"preload=true", "max_age=-1", "colour=blue", "enabled="} {
+		if _, err := s.Parse(input, current); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := map[string]any{"ssl": "full", "brotli": "on", "browser_cache_ttl": float64(14400), "http2": "on"}
+	b := map[string]any{"ssl": "strict", "brotli": "on", "browser_cache_ttl": 14400}
+
+	diffs := Diff(a, b)
+	if len(diffs) != 2 {
+		t.Fatalf("got %d differences, want 2: %+v", len(diffs), diffs)
+	}
+	if diffs[0].ID != "http2" || diffs[0].OtherValue != "-" {
+		t.Errorf("got %+v first", diffs[0])
+	}
+	if diffs[1].ID != "ssl" || diffs[1].Value != "full" || diffs[1].OtherValue != "strict" {
+		t.Errorf("got %+v second", diffs[1])
+	}
+}
+
+func TestPlan(t *testing.T) {
+	baseline, err := ParseBaseline([]byte(`
+ssl: strict
+always-use-https: on
+min_tls_version: 1.0
+browser_cache_ttl: 14400
+hsts:
+  enabled: true
+  max_age: 31536000
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := map[string]any{
+		"ssl":               "full",
+		"always_use_https":  "on",
+		"min_tls_version":   "1.2",
+		"browser_cache_ttl": float64(14400),
+	}
+
+	changes, err := Plan(current, baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, c := range changes {
+		ids = append(ids, c.ID+"="+c.New)
+	}
+	got := strings.Join(ids, " ")
+	want := "min_tls_version=1.0 security_header=enabled=true,max_age=31536000,include_subdomains=false,preload=false,nosniff=false ssl=strict"
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+
+	bad, _ := ParseBaseline([]byte("ssl: sometimes\nbogus: on\nsecurity_level: high\n"))
+	_, err = Plan(current, bad)
+	if err == nil || !strings.Contains(err.Error(), "ssl") || !strings.Contains(err.Error(), "bogus") {
+		t.Errorf("Plan with bad baseline: got error %v", err)
+	}
+}